@@ -0,0 +1,281 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/go-errors/errors"
+)
+
+// Advisory is a vulnerability reported by `npm audit`
+type Advisory struct {
+	// Source is the advisory's id in v6 reports. In v7+ reports we get one
+	// entry per vulnerable package so there it's the package's name
+	Source   string
+	Name     string
+	Title    string
+	Severity string
+	// Range is the range of versions of the package that are vulnerable
+	Range        string
+	URL          string
+	FixAvailable bool
+	// FixIsBreaking is true when the only fix is a semver-major update
+	FixIsBreaking bool
+	// each path goes from a direct dependency down to the vulnerable package
+	Paths [][]string
+}
+
+func (a *Advisory) ID() string {
+	return fmt.Sprintf("advisory:%s|%s", a.Source, a.Name)
+}
+
+// DirectDependencies returns the names of the current package's dependencies
+// that pull in the vulnerable package
+func (a *Advisory) DirectDependencies() []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, path := range a.Paths {
+		if len(path) == 0 || seen[path[0]] {
+			continue
+		}
+		seen[path[0]] = true
+		names = append(names, path[0])
+	}
+	return names
+}
+
+// SeverityRank lets us sort advisories with the worst first
+func SeverityRank(severity string) int {
+	return map[string]int{
+		"critical": 4,
+		"high":     3,
+		"moderate": 2,
+		"low":      1,
+	}[severity]
+}
+
+// max number of dependency paths we'll find per advisory in a v7+ report,
+// given a big dependency graph can have an absurd number of them
+const maxAuditPaths = 20
+
+// ParseAudit parses the output of `npm audit --json`. npm 6 (and pnpm) give
+// us advisories keyed by id whereas npm 7+ gives us vulnerabilities keyed by
+// package name, so we handle both.
+func ParseAudit(output string) ([]*Advisory, error) {
+	output = strings.TrimSpace(output)
+	data := []byte(output)
+	if idx := strings.Index(output, "{"); idx > 0 {
+		data = data[idx:]
+	}
+
+	if errData, dataType, _, err := jsonparser.Get(data, "error"); err == nil && dataType == jsonparser.Object {
+		summary, _ := jsonparser.GetString(errData, "summary")
+		return nil, errors.New(summary)
+	}
+
+	var advisories []*Advisory
+	var err error
+	if _, dataType, _, getErr := jsonparser.Get(data, "vulnerabilities"); getErr == nil && dataType == jsonparser.Object {
+		advisories, err = parseAuditV7(data)
+	} else {
+		advisories, err = parseAuditV6(data)
+	}
+	if err != nil {
+		return nil, errors.New("could not parse audit output: " + output)
+	}
+
+	sort.SliceStable(advisories, func(i, j int) bool {
+		if SeverityRank(advisories[i].Severity) != SeverityRank(advisories[j].Severity) {
+			return SeverityRank(advisories[i].Severity) > SeverityRank(advisories[j].Severity)
+		}
+		return advisories[i].Name < advisories[j].Name
+	})
+
+	return advisories, nil
+}
+
+func parseAuditV6(data []byte) ([]*Advisory, error) {
+	// actions tell us which advisories can be fixed and how
+	fixable := map[string]bool{}
+	fixableWithoutBreaking := map[string]bool{}
+	_, _ = jsonparser.ArrayEach(data, func(action []byte, dataType jsonparser.ValueType, offset int, err error) {
+		actionType, _ := jsonparser.GetString(action, "action")
+		if actionType == "review" {
+			return
+		}
+		isMajor, _ := jsonparser.GetBoolean(action, "isMajor")
+		_, _ = jsonparser.ArrayEach(action, func(resolve []byte, dataType jsonparser.ValueType, offset int, err error) {
+			id, err := jsonparser.GetInt(resolve, "id")
+			if err != nil {
+				return
+			}
+			source := strconv.FormatInt(id, 10)
+			fixable[source] = true
+			if !isMajor {
+				fixableWithoutBreaking[source] = true
+			}
+		}, "resolves")
+	}, "actions")
+
+	advisories := []*Advisory{}
+	err := jsonparser.ObjectEach(data, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		if dataType != jsonparser.Object {
+			return nil
+		}
+		advisory := &Advisory{Source: unescape(key)}
+		for _, mapping := range []struct {
+			field string
+			ptr   *string
+		}{
+			{field: "module_name", ptr: &advisory.Name},
+			{field: "title", ptr: &advisory.Title},
+			{field: "severity", ptr: &advisory.Severity},
+			{field: "vulnerable_versions", ptr: &advisory.Range},
+			{field: "url", ptr: &advisory.URL},
+		} {
+			str, err := jsonparser.GetString(value, mapping.field)
+			if err == nil {
+				*mapping.ptr = str
+			}
+		}
+		advisory.FixAvailable = fixable[advisory.Source]
+		advisory.FixIsBreaking = fixable[advisory.Source] && !fixableWithoutBreaking[advisory.Source]
+
+		_, _ = jsonparser.ArrayEach(value, func(finding []byte, dataType jsonparser.ValueType, offset int, err error) {
+			_, _ = jsonparser.ArrayEach(finding, func(path []byte, dataType jsonparser.ValueType, offset int, err error) {
+				if dataType == jsonparser.String {
+					advisory.Paths = append(advisory.Paths, strings.Split(string(path), ">"))
+				}
+			}, "paths")
+		}, "findings")
+
+		advisories = append(advisories, advisory)
+		return nil
+	}, "advisories")
+	if err != nil && err != jsonparser.KeyPathNotFoundError {
+		return nil, err
+	}
+
+	return advisories, nil
+}
+
+type auditVulnerability struct {
+	advisory *Advisory
+	isDirect bool
+	// effects are the packages which are vulnerable because they depend on this one
+	effects []string
+}
+
+func parseAuditV7(data []byte) ([]*Advisory, error) {
+	vulnerabilities := map[string]*auditVulnerability{}
+	advisories := []*Advisory{}
+
+	err := jsonparser.ObjectEach(data, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		if dataType != jsonparser.Object {
+			return nil
+		}
+		name := unescape(key)
+		advisory := &Advisory{Source: name, Name: name}
+		advisory.Severity, _ = jsonparser.GetString(value, "severity")
+		advisory.Range, _ = jsonparser.GetString(value, "range")
+
+		fixAvailable, fixDataType, _, err := jsonparser.Get(value, "fixAvailable")
+		if err == nil {
+			switch fixDataType {
+			case jsonparser.Boolean:
+				advisory.FixAvailable = string(fixAvailable) == "true"
+			case jsonparser.Object:
+				advisory.FixAvailable = true
+				advisory.FixIsBreaking, _ = jsonparser.GetBoolean(fixAvailable, "isSemVerMajor")
+			}
+		}
+
+		// 'via' contains either the advisories themselves or the names of the
+		// vulnerable packages that make this package vulnerable
+		titles := []string{}
+		viaNames := []string{}
+		_, _ = jsonparser.ArrayEach(value, func(via []byte, dataType jsonparser.ValueType, offset int, err error) {
+			switch dataType {
+			case jsonparser.String:
+				viaNames = append(viaNames, string(via))
+			case jsonparser.Object:
+				if title, err := jsonparser.GetString(via, "title"); err == nil {
+					titles = append(titles, title)
+				}
+				if url, err := jsonparser.GetString(via, "url"); err == nil && advisory.URL == "" {
+					advisory.URL = url
+				}
+			}
+		}, "via")
+		if len(titles) > 0 {
+			advisory.Title = strings.Join(titles, "; ")
+		} else {
+			advisory.Title = "via " + strings.Join(viaNames, ", ")
+		}
+
+		vulnerability := &auditVulnerability{advisory: advisory}
+		vulnerability.isDirect, _ = jsonparser.GetBoolean(value, "isDirect")
+		_, _ = jsonparser.ArrayEach(value, func(effect []byte, dataType jsonparser.ValueType, offset int, err error) {
+			vulnerability.effects = append(vulnerability.effects, string(effect))
+		}, "effects")
+
+		vulnerabilities[name] = vulnerability
+		advisories = append(advisories, advisory)
+		return nil
+	}, "vulnerabilities")
+	if err != nil {
+		return nil, err
+	}
+
+	for name, vulnerability := range vulnerabilities {
+		vulnerability.advisory.Paths = auditPaths(name, vulnerabilities, map[string]bool{})
+	}
+
+	return advisories, nil
+}
+
+// auditPaths follows a vulnerability's effects back up to the direct dependencies
+func auditPaths(name string, vulnerabilities map[string]*auditVulnerability, visited map[string]bool) [][]string {
+	vulnerability := vulnerabilities[name]
+	if vulnerability == nil || visited[name] {
+		return nil
+	}
+	visited[name] = true
+	defer delete(visited, name)
+
+	paths := [][]string{}
+	if vulnerability.isDirect {
+		paths = append(paths, []string{name})
+	}
+	for _, effect := range vulnerability.effects {
+		for _, path := range auditPaths(effect, vulnerabilities, visited) {
+			if len(paths) >= maxAuditPaths {
+				return paths
+			}
+			paths = append(paths, append(append([]string{}, path...), name))
+		}
+	}
+	return paths
+}
+
+// GetAdvisories returns nil if the package's package manager can't audit as json
+func (m *NpmManager) GetAdvisories(pkg *Package, opts CmdOpts) ([]*Advisory, error) {
+	cmdStr := pkg.PackageManager.Audit(opts)
+	if cmdStr == "" {
+		return nil, nil
+	}
+
+	// npm audit exits with a non-zero code when it finds vulnerabilities
+	output, cmdErr := m.OSCommand.RunCommandWithOutput(cmdStr)
+	advisories, err := ParseAudit(output)
+	if err != nil {
+		if cmdErr != nil && !strings.Contains(output, "{") {
+			return nil, cmdErr
+		}
+		return nil, err
+	}
+	return advisories, nil
+}
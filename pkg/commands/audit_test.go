@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAudit(t *testing.T) {
+	type scenario struct {
+		filename string
+		expected []*Advisory
+	}
+
+	scenarios := []scenario{
+		{
+			"testfiles/audit/v6.json",
+			[]*Advisory{
+				{
+					Source:       "1523",
+					Name:         "lodash",
+					Title:        "Prototype Pollution",
+					Severity:     "high",
+					Range:        "<4.17.19",
+					URL:          "https://npmjs.com/advisories/1523",
+					FixAvailable: true,
+					Paths:        [][]string{{"a", "lodash"}, {"lodash"}},
+				},
+				{
+					Source:   "42",
+					Name:     "unfixable",
+					Title:    "Something bad",
+					Severity: "moderate",
+					Range:    "*",
+					URL:      "https://npmjs.com/advisories/42",
+					Paths:    [][]string{{"b", "unfixable"}},
+				},
+				{
+					Source:        "1179",
+					Name:          "minimist",
+					Title:         "Prototype Pollution",
+					Severity:      "low",
+					Range:         "<0.2.1 || >=1.0.0 <1.2.3",
+					URL:           "https://npmjs.com/advisories/1179",
+					FixAvailable:  true,
+					FixIsBreaking: true,
+					Paths:         [][]string{{"mkdirp", "minimist"}},
+				},
+			},
+		},
+		{
+			"testfiles/audit/v7.json",
+			[]*Advisory{
+				{
+					Source:        "minimist",
+					Name:          "minimist",
+					Title:         "Prototype Pollution in minimist",
+					Severity:      "critical",
+					Range:         "<=0.2.3",
+					URL:           "https://github.com/advisories/GHSA-xvch-5gv4-984h",
+					FixAvailable:  true,
+					FixIsBreaking: true,
+					Paths:         [][]string{{"mkdirp", "minimist"}},
+				},
+				{
+					Source:        "mkdirp",
+					Name:          "mkdirp",
+					Title:         "via minimist",
+					Severity:      "critical",
+					Range:         "0.4.1 - 0.5.1",
+					FixAvailable:  true,
+					FixIsBreaking: true,
+					Paths:         [][]string{{"mkdirp"}},
+				},
+				{
+					Source:       "semver",
+					Name:         "semver",
+					Title:        "semver vulnerable to Regular Expression Denial of Service",
+					Severity:     "moderate",
+					Range:        "<5.7.2",
+					URL:          "https://github.com/advisories/GHSA-c2qf-rxjj-qqgw",
+					FixAvailable: true,
+					Paths:        [][]string{{"semver"}},
+				},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.filename, func(t *testing.T) {
+			output, err := ioutil.ReadFile(s.filename)
+			assert.NoError(t, err)
+
+			advisories, err := ParseAudit(string(output))
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expected, advisories)
+		})
+	}
+}
+
+func TestParseAuditError(t *testing.T) {
+	_, err := ParseAudit(`{"error": {"code": "ENOLOCK", "summary": "This command requires an existing lockfile."}}`)
+	assert.EqualError(t, err, "This command requires an existing lockfile.")
+}
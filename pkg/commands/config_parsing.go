@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"io"
+	"strings"
 
 	"github.com/buger/jsonparser"
 )
@@ -77,6 +78,14 @@ func UnmarshalPackageConfig(r io.Reader, previousPackageConfig *PackageConfig) (
 				path: []string{"main"},
 				ptr:  &pkgConfig.Main,
 			},
+			{
+				path: []string{"deprecated"},
+				ptr:  &pkgConfig.Deprecated,
+			},
+			{
+				path: []string{"packageManager"},
+				ptr:  &pkgConfig.PackageManager,
+			},
 			{
 				path: []string{"engines", "node"},
 				ptr:  &pkgConfig.Engines.Node,
@@ -124,14 +133,30 @@ func UnmarshalPackageConfig(r io.Reader, previousPackageConfig *PackageConfig) (
 		},
 	)
 
+	// older packages give their license as an object, or a list of objects
+	// meaning the user can pick any of them
+	if pkgConfig.License == "" {
+		if license, err := jsonparser.GetString(configData, "license", "type"); err == nil {
+			pkgConfig.License = license
+		} else {
+			licenses := []string{}
+			_, _ = jsonparser.ArrayEach(configData, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
+				if license, err := jsonparser.GetString(value, "type"); err == nil {
+					licenses = append(licenses, license)
+				}
+			}, "licenses")
+			if len(licenses) == 1 {
+				pkgConfig.License = licenses[0]
+			} else if len(licenses) > 1 {
+				pkgConfig.License = "(" + strings.Join(licenses, " OR ") + ")"
+			}
+		}
+	}
+
 	for _, mapping := range []struct {
 		field string
 		ptr   *bool
 	}{
-		{
-			field: "deprecated",
-			ptr:   &pkgConfig.Deprecated,
-		},
 		{
 			field: "private",
 			ptr:   &pkgConfig.Private,
@@ -172,6 +197,10 @@ func UnmarshalPackageConfig(r io.Reader, previousPackageConfig *PackageConfig) (
 			field: "bundledDependencies",
 			ptr:   &pkgConfig.BundledDependencies,
 		},
+		{
+			field: "workspaces",
+			ptr:   &pkgConfig.Workspaces,
+		},
 	} {
 		value, dataType, _, err := jsonparser.Get(configData, mapping.field)
 		if err != nil {
@@ -180,6 +209,13 @@ func UnmarshalPackageConfig(r io.Reader, previousPackageConfig *PackageConfig) (
 			}
 			return nil, err
 		}
+		// workspaces can also be given as an object like { "packages": [...] }
+		if mapping.field == "workspaces" && dataType == jsonparser.Object {
+			value, dataType, _, err = jsonparser.Get(value, "packages")
+			if err != nil {
+				continue
+			}
+		}
 		switch dataType {
 		case jsonparser.Array:
 			_, _ = jsonparser.ArrayEach(value, func(innerValue []byte, dataType jsonparser.ValueType, offset int, err error) {
@@ -220,6 +256,10 @@ func UnmarshalPackageConfig(r io.Reader, previousPackageConfig *PackageConfig) (
 			field: "optionalDependencies",
 			ptr:   &pkgConfig.OptionalDependencies,
 		},
+		{
+			field: "bin",
+			ptr:   &pkgConfig.Bin,
+		},
 	} {
 		value, dataType, _, err := jsonparser.Get(configData, mapping.field)
 		if err != nil {
@@ -242,6 +282,25 @@ func UnmarshalPackageConfig(r io.Reader, previousPackageConfig *PackageConfig) (
 		}
 	}
 
+	// a bin given as a single path provides a command named after the package,
+	// without its scope
+	if bin, err := jsonparser.GetString(configData, "bin"); err == nil && pkgConfig.Name != "" {
+		name := pkgConfig.Name[strings.LastIndex(pkgConfig.Name, "/")+1:]
+		pkgConfig.Bin = map[string]string{name: bin}
+	}
+
+	_ = jsonparser.ObjectEach(configData, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		if dataType != jsonparser.Object {
+			return nil
+		}
+		if pkgConfig.PeerDependenciesMeta == nil {
+			pkgConfig.PeerDependenciesMeta = map[string]PeerDependencyMeta{}
+		}
+		optional, _ := jsonparser.GetBoolean(value, "optional")
+		pkgConfig.PeerDependenciesMeta[unescape(key)] = PeerDependencyMeta{Optional: optional}
+		return nil
+	}, "peerDependenciesMeta")
+
 	value, dataType, _, err := jsonparser.Get(configData, "contributors")
 	if err != nil {
 		if err != jsonparser.KeyPathNotFoundError {
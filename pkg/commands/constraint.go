@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jesseduffield/semver/v3"
+)
+
+// ConstraintStyle is a way of turning a version into a constraint e.g. caret
+// turns 1.2.3 into ^1.2.3
+type ConstraintStyle struct {
+	Name        string
+	Description string
+	format      func(version *semver.Version) string
+}
+
+// Format returns the constraint for the given version in this style
+func (s ConstraintStyle) Format(version string) (string, error) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return "", err
+	}
+	return s.format(v), nil
+}
+
+// ConstraintStyles are the styles we offer when editing a constraint. Range is
+// meant as a starting point for the user to adjust
+func ConstraintStyles() []ConstraintStyle {
+	return []ConstraintStyle{
+		{
+			Name:        "caret",
+			Description: "allow minor and patch updates",
+			format:      func(v *semver.Version) string { return "^" + v.String() },
+		},
+		{
+			Name:        "tilde",
+			Description: "allow patch updates",
+			format:      func(v *semver.Version) string { return "~" + v.String() },
+		},
+		{
+			Name:        "exact",
+			Description: "only this version",
+			format:      func(v *semver.Version) string { return v.String() },
+		},
+		{
+			Name:        "range",
+			Description: "this version up to the next major version",
+			format: func(v *semver.Version) string {
+				return fmt.Sprintf(">=%s <%d.0.0", v.String(), v.Major()+1)
+			},
+		},
+	}
+}
+
+// ConstraintPreview says what changing a dependency's constraint would mean for
+// what's installed
+type ConstraintPreview struct {
+	Constraint string
+	// Installed is empty if the dependency isn't installed
+	Installed string
+	// Err is set if the constraint isn't a semver range, like a git url or a
+	// dist-tag, in which case we can't say anything about it
+	Err error
+	// Satisfied is true if the installed version satisfies the constraint
+	Satisfied bool
+	// Resolved is the newest of the available versions that satisfies the
+	// constraint, which is what a fresh install would pick
+	Resolved string
+}
+
+// PreviewConstraint checks the constraint against the installed version and
+// the versions available to install
+func PreviewConstraint(constraint string, installed string, versions []string) *ConstraintPreview {
+	preview := &ConstraintPreview{Constraint: strings.TrimSpace(constraint), Installed: installed}
+
+	c, err := semver.NewConstraint(preview.Constraint)
+	if err != nil {
+		preview.Err = err
+		return preview
+	}
+
+	if installed != "" {
+		if v, err := semver.NewVersion(installed); err == nil {
+			preview.Satisfied = c.Check(v)
+		}
+	}
+
+	var resolved *semver.Version
+	for _, version := range versions {
+		v, err := semver.NewVersion(version)
+		if err != nil || !c.Check(v) {
+			continue
+		}
+		if resolved == nil || v.GreaterThan(resolved) {
+			resolved = v
+			preview.Resolved = version
+		}
+	}
+
+	return preview
+}
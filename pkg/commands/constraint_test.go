@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstraintStylesFormat(t *testing.T) {
+	expected := map[string]string{
+		"caret": "^1.2.3",
+		"tilde": "~1.2.3",
+		"exact": "1.2.3",
+		"range": ">=1.2.3 <2.0.0",
+	}
+
+	for _, style := range ConstraintStyles() {
+		constraint, err := style.Format("v1.2.3")
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected[style.Name], constraint, style.Name)
+	}
+
+	_, err := ConstraintStyles()[0].Format("not-a-version")
+	assert.Error(t, err)
+}
+
+func TestPreviewConstraint(t *testing.T) {
+	type scenario struct {
+		name              string
+		constraint        string
+		installed         string
+		expectedSatisfied bool
+		expectedResolved  string
+		expectedErr       bool
+	}
+
+	versions := []string{"2.0.0", "2.0.0-beta.1", "1.3.0", "1.2.3", "1.2.0"}
+
+	scenarios := []scenario{
+		{name: "caret", constraint: "^1.2.0", installed: "1.2.3", expectedSatisfied: true, expectedResolved: "1.3.0"},
+		{name: "tilde", constraint: "~1.2.0", installed: "1.3.0", expectedSatisfied: false, expectedResolved: "1.2.3"},
+		{name: "exact", constraint: "1.2.0", installed: "1.2.3", expectedSatisfied: false, expectedResolved: "1.2.0"},
+		{name: "range", constraint: ">=1.2.3 <2.0.0", installed: "1.2.3", expectedSatisfied: true, expectedResolved: "1.3.0"},
+		{name: "prereleases aren't picked", constraint: ">=2.0.0-alpha", installed: "", expectedSatisfied: false, expectedResolved: "2.0.0"},
+		{name: "nothing satisfies", constraint: "^3.0.0", installed: "1.2.3", expectedSatisfied: false, expectedResolved: ""},
+		{name: "not installed", constraint: "^1.0.0", installed: "", expectedSatisfied: false, expectedResolved: "1.3.0"},
+		{name: "git url", constraint: "github:user/repo", installed: "1.2.3", expectedErr: true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			preview := PreviewConstraint(s.constraint, s.installed, versions)
+			assert.EqualValues(t, s.expectedErr, preview.Err != nil)
+			assert.EqualValues(t, s.expectedSatisfied, preview.Satisfied)
+			assert.EqualValues(t, s.expectedResolved, preview.Resolved)
+		})
+	}
+}
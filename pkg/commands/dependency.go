@@ -14,12 +14,41 @@ type Dependency struct {
 	Path              string
 	Kind              string
 	ParentPackagePath string
+	// Locked is the dependency's entry in the lockfile, if any
+	Locked *LockedPackage
+	// LockDrift is true when what's installed in node_modules doesn't match the lockfile
+	LockDrift bool
+	// Outdated is nil if we don't know whether the dependency is outdated
+	Outdated *OutdatedInfo
+	// DiskUsage is nil until we've read node_modules
+	DiskUsage *DiskUsage
+	// EngineViolations are the engines the installed package isn't happy with
+	EngineViolations []*EngineViolation
+	// LicenseViolations are the packages the dependency pulls in (including
+	// itself) whose license breaks the user's policy
+	LicenseViolations []*LicensedPackage
+	// Deprecated is the deprecation message for the installed version, if any
+	Deprecated string
+	// DeprecatedDependencies are the deprecated packages the dependency pulls in
+	DeprecatedDependencies []*DeprecatedPackage
 }
 
 func (d *Dependency) Linked() bool {
 	return d.LinkPath != ""
 }
 
+// hasLockDrift assumes the package has a lockfile. Linked deps are expected
+// to differ from the lockfile so we don't count them.
+func (d *Dependency) hasLockDrift() bool {
+	if !d.Present || d.Linked() || d.PackageConfig == nil {
+		return false
+	}
+	if d.Locked == nil {
+		return true
+	}
+	return !d.Locked.Link && d.Locked.Version != d.PackageConfig.Version
+}
+
 func (d *Dependency) ConfigPath() string {
 	return filepath.Join(d.Path, "package.json")
 }
@@ -0,0 +1,51 @@
+package commands
+
+import "sort"
+
+// directDependency is one of the root package's dependencies as installed
+type directDependency struct {
+	name string
+	path string
+	kind string
+}
+
+// dependencyGraph flips the index so that it points downwards, returning the
+// installed dependencies of each package along with the root package's direct
+// dependencies
+func (index *DependentsIndex) dependencyGraph() (map[string][]string, []directDependency) {
+	dependencies := map[string][]string{}
+	directDeps := []directDependency{}
+	for target, dependents := range index.dependents {
+		for _, dependent := range dependents {
+			if dependent.path == "" {
+				directDeps = append(directDeps, directDependency{name: installedName(target), path: target, kind: dependent.kind})
+				continue
+			}
+			dependencies[dependent.path] = append(dependencies[dependent.path], target)
+		}
+	}
+	sort.Slice(directDeps, func(i, j int) bool {
+		if directDeps[i].name != directDeps[j].name {
+			return directDeps[i].name < directDeps[j].name
+		}
+		return directDeps[i].kind < directDeps[j].kind
+	})
+	return dependencies, directDeps
+}
+
+// reachableFrom returns every package that the given package pulls in,
+// including itself
+func reachableFrom(dependencies map[string][]string, path string) map[string]bool {
+	visited := map[string]bool{}
+	queue := []string{path}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		queue = append(queue, dependencies[current]...)
+	}
+	return visited
+}
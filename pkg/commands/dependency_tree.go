@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DepNode is a package in the tree of packages installed in node_modules
+type DepNode struct {
+	Name string
+	// Constraint is the version constraint that the parent package asked for
+	Constraint string
+	// Path is where node would resolve the package to. Empty if it's missing
+	Path          string
+	LinkPath      string
+	PackageConfig *PackageConfig
+	// Deduped is true if the package has already been resolved elsewhere in
+	// the tree, in which case we don't expand it again
+	Deduped  bool
+	Depth    int
+	Expanded bool
+	// Children is nil until we've loaded them
+	Children []*DepNode
+	Parent   *DepNode
+}
+
+func (n *DepNode) Missing() bool {
+	return n.Path == ""
+}
+
+func (n *DepNode) Linked() bool {
+	return n.LinkPath != ""
+}
+
+func (n *DepNode) ID() string {
+	names := []string{}
+	for node := n; node != nil; node = node.Parent {
+		names = append([]string{node.Name}, names...)
+	}
+	return fmt.Sprintf("depnode:%s", strings.Join(names, ">"))
+}
+
+// Expandable tells us whether it's worth trying to load the node's children
+func (n *DepNode) Expandable() bool {
+	if n.Missing() || n.Deduped || n.PackageConfig == nil {
+		return false
+	}
+	return len(n.PackageConfig.Dependencies)+len(n.PackageConfig.OptionalDependencies) > 0
+}
+
+// Flatten returns the nodes which are visible given which nodes are expanded
+func (n *DepNode) Flatten() []*DepNode {
+	nodes := []*DepNode{n}
+	if !n.Expanded {
+		return nodes
+	}
+	for _, child := range n.Children {
+		nodes = append(nodes, child.Flatten()...)
+	}
+	return nodes
+}
+
+// resolvedPaths returns the paths of all the loaded nodes in the tree which
+// have not been deduped
+func (n *DepNode) resolvedPaths(paths map[string]bool) {
+	if !n.Missing() && !n.Deduped {
+		paths[n.Path] = true
+	}
+	for _, child := range n.Children {
+		child.resolvedPaths(paths)
+	}
+}
+
+func (n *DepNode) root() *DepNode {
+	root := n
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	return root
+}
+
+// ResolveDep finds the directory that node would load the named package from
+// when required by a package in the given directory, by checking each
+// node_modules folder on the way up. Returns an empty string if the package
+// can't be found.
+func ResolveDep(fromDir string, name string) string {
+	dir := fromDir
+	for {
+		if filepath.Base(dir) != "node_modules" {
+			candidate := filepath.Join(dir, "node_modules", name)
+			if FileExists(filepath.Join(candidate, "package.json")) {
+				return candidate
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// NewDepTree returns the root node of the tree of packages installed for a dependency
+func (m *NpmManager) NewDepTree(dep *Dependency) *DepNode {
+	node := &DepNode{Name: dep.Name, Constraint: dep.Constraint}
+	if dep.Present {
+		node.Path = dep.Path
+		node.LinkPath = dep.LinkPath
+		node.PackageConfig = dep.PackageConfig
+	}
+	return node
+}
+
+// LoadDepNodeChildren resolves the node's own dependencies, if we haven't already
+func (m *NpmManager) LoadDepNodeChildren(node *DepNode) error {
+	if node.Children != nil || !node.Expandable() {
+		return nil
+	}
+
+	// linked packages resolve their dependencies from where they actually live
+	fromDir := node.Path
+	if node.Linked() {
+		fromDir = node.LinkPath
+	}
+
+	resolvedPaths := map[string]bool{}
+	node.root().resolvedPaths(resolvedPaths)
+
+	constraints := map[string]string{}
+	for _, depMap := range []map[string]string{node.PackageConfig.Dependencies, node.PackageConfig.OptionalDependencies} {
+		for name, constraint := range depMap {
+			constraints[name] = constraint
+		}
+	}
+
+	children := make([]*DepNode, 0, len(constraints))
+	for name, constraint := range constraints {
+		child := &DepNode{
+			Name:       name,
+			Constraint: constraint,
+			Path:       ResolveDep(fromDir, name),
+			Depth:      node.Depth + 1,
+			Parent:     node,
+		}
+		if !child.Missing() {
+			if err := m.loadDepNode(child); err != nil {
+				return err
+			}
+		}
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+
+	// doing this after sorting so that it's the first occurrence that gets expanded
+	for _, child := range children {
+		if child.Missing() {
+			continue
+		}
+		if resolvedPaths[child.Path] {
+			child.Deduped = true
+		} else {
+			resolvedPaths[child.Path] = true
+		}
+	}
+
+	node.Children = children
+	return nil
+}
+
+func (m *NpmManager) loadDepNode(node *DepNode) error {
+	fileInfo, err := os.Lstat(node.Path)
+	if err != nil {
+		return err
+	}
+	if fileInfo.Mode()&os.ModeSymlink == os.ModeSymlink {
+		linkPath, err := filepath.EvalSymlinks(node.Path)
+		if err != nil {
+			return err
+		}
+		node.LinkPath = linkPath
+	}
+
+	pkgConfig, err := m.getPackageConfig(node.Path)
+	if err != nil {
+		// swallowing error
+		m.Log.Error(err)
+		return nil
+	}
+	node.PackageConfig = pkgConfig
+	return nil
+}
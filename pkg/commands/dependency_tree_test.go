@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadDepNodeChildren(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	writePackageTree(t, rootPath, map[string]string{
+		"node_modules/a":                `{"name": "a", "version": "1.0.0", "dependencies": {"b": "^2.0.0", "c": "^1.0.0", "missing": "*"}}`,
+		"node_modules/a/node_modules/b": `{"name": "b", "version": "2.0.0"}`,
+		"node_modules/b":                `{"name": "b", "version": "1.0.0"}`,
+		"node_modules/c":                `{"name": "c", "version": "1.0.0", "dependencies": {"b": "^1.0.0"}, "optionalDependencies": {"a": "^1.0.0"}}`,
+	})
+
+	m := NewDummyNpmManager()
+	pkgConfig, err := m.getPackageConfig(filepath.Join(rootPath, "node_modules/a"))
+	assert.NoError(t, err)
+
+	root := m.NewDepTree(&Dependency{
+		Name:          "a",
+		Constraint:    "^1.0.0",
+		Present:       true,
+		Path:          filepath.Join(rootPath, "node_modules/a"),
+		PackageConfig: pkgConfig,
+	})
+	assert.NoError(t, m.LoadDepNodeChildren(root))
+
+	type nodeSummary struct {
+		name    string
+		path    string
+		deduped bool
+	}
+	summarise := func(nodes []*DepNode) []nodeSummary {
+		summaries := make([]nodeSummary, len(nodes))
+		for i, node := range nodes {
+			path := ""
+			if !node.Missing() {
+				path, err = filepath.Rel(rootPath, node.Path)
+				assert.NoError(t, err)
+			}
+			summaries[i] = nodeSummary{name: node.Name, path: path, deduped: node.Deduped}
+		}
+		return summaries
+	}
+
+	// nested node_modules take precedence over hoisted ones
+	assert.EqualValues(t, []nodeSummary{
+		{name: "b", path: "node_modules/a/node_modules/b"},
+		{name: "c", path: "node_modules/c"},
+		{name: "missing"},
+	}, summarise(root.Children))
+
+	c := root.Children[1]
+	assert.NoError(t, m.LoadDepNodeChildren(c))
+	// a is already in the tree so it's deduped
+	assert.EqualValues(t, []nodeSummary{
+		{name: "a", path: "node_modules/a", deduped: true},
+		{name: "b", path: "node_modules/b"},
+	}, summarise(c.Children))
+	assert.EqualValues(t, 2, c.Children[1].Depth)
+
+	root.Expanded = true
+	assert.EqualValues(t, 4, len(root.Flatten()))
+	c.Expanded = true
+	assert.EqualValues(t, 6, len(root.Flatten()))
+}
@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// DeprecatedPackage is an installed package whose publisher has deprecated it
+type DeprecatedPackage struct {
+	Name    string
+	Version string
+	// Path is relative to the root package
+	Path    string
+	Message string
+	// Via are the names of the root package's direct dependencies that pull
+	// the package in (including the package itself, if it's a direct dependency)
+	Via []string
+}
+
+// Deprecations finds every installed package that's been deprecated, along
+// with the direct dependencies that bring it in. Packages that nothing depends
+// on are left out. npm records deprecation messages from the registry in the
+// lockfile when installing, and some packages say so in their own
+// package.json. registryMessages maps install paths to messages we've fetched
+// from the registry ourselves
+func (index *DependentsIndex) Deprecations(lockfile *Lockfile, registryMessages map[string]string) []*DeprecatedPackage {
+	dependencies, directDeps := index.dependencyGraph()
+
+	via := map[string][]string{}
+	for _, directDep := range directDeps {
+		for path := range reachableFrom(dependencies, directDep.path) {
+			if !utils.IncludesString(via[path], directDep.name) {
+				via[path] = append(via[path], directDep.name)
+			}
+		}
+	}
+
+	deprecations := []*DeprecatedPackage{}
+	for path, pkgConfig := range index.packages {
+		if len(via[path]) == 0 {
+			continue
+		}
+		message := index.deprecationMessage(path, pkgConfig, lockfile, registryMessages)
+		if message == "" {
+			continue
+		}
+		relPath, err := filepath.Rel(index.rootPath, path)
+		if err != nil {
+			relPath = path
+		}
+		deprecations = append(deprecations, &DeprecatedPackage{
+			Name:    pkgConfig.Name,
+			Version: pkgConfig.Version,
+			Path:    relPath,
+			Message: message,
+			Via:     via[path],
+		})
+	}
+
+	sort.Slice(deprecations, func(i, j int) bool {
+		if deprecations[i].Name != deprecations[j].Name {
+			return deprecations[i].Name < deprecations[j].Name
+		}
+		return deprecations[i].Path < deprecations[j].Path
+	})
+	return deprecations
+}
+
+func (index *DependentsIndex) deprecationMessage(path string, pkgConfig *PackageConfig, lockfile *Lockfile, registryMessages map[string]string) string {
+	if pkgConfig.Deprecated != "" {
+		return pkgConfig.Deprecated
+	}
+	if message := registryMessages[path]; message != "" {
+		return message
+	}
+	if lockfile == nil {
+		return ""
+	}
+	relPath, err := filepath.Rel(filepath.Dir(lockfile.Path), path)
+	if err != nil {
+		return ""
+	}
+	lockedPkg := lockfile.Packages[filepath.ToSlash(relPath)]
+	// if the lockfile is out of date its message may be about another version
+	if lockedPkg == nil || lockedPkg.Version != pkgConfig.Version {
+		return ""
+	}
+	return lockedPkg.Deprecated
+}
+
+// maxRegistryRequests is how many packages we'll fetch from the registry at once
+const maxRegistryRequests = 8
+
+// GetRegistryDeprecations asks the registry whether the installed version of
+// each of the package's direct dependencies has been deprecated, returning
+// messages keyed by install path. Lockfiles only know about deprecations from
+// before the last install, so this catches the ones since. Only direct
+// dependencies are checked given there can be thousands of transitive ones:
+// for those we rely on the lockfile and node_modules. Packages we can't fetch
+// are skipped, and answers are remembered for each name@version.
+func (m *NpmManager) GetRegistryDeprecations(pkg *Package, index *DependentsIndex) map[string]string {
+	npmConfig := m.GetNpmConfig(pkg)
+	_, directDeps := index.dependencyGraph()
+
+	messages := map[string]string{}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxRegistryRequests)
+	fetched := map[string]bool{}
+	for _, directDep := range directDeps {
+		pkgConfig := index.packages[directDep.path]
+		if pkgConfig == nil || pkgConfig.Version == "" || fetched[directDep.path] {
+			continue
+		}
+		fetched[directDep.path] = true
+		wg.Add(1)
+		go func(path string, pkgConfig *PackageConfig) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			message, err := m.getRegistryDeprecation(npmConfig, pkgConfig)
+			if err != nil {
+				m.Log.Warn(err)
+				return
+			}
+			if message != "" {
+				mutex.Lock()
+				messages[path] = message
+				mutex.Unlock()
+			}
+		}(directDep.path, pkgConfig)
+	}
+	wg.Wait()
+
+	return messages
+}
+
+// getRegistryDeprecation returns the version's deprecation message, or an
+// empty string if it's not deprecated
+func (m *NpmManager) getRegistryDeprecation(npmConfig *NpmConfig, pkgConfig *PackageConfig) (string, error) {
+	key := pkgConfig.Name + "@" + pkgConfig.Version
+
+	m.registryDeprecationMutex.Lock()
+	message, ok := m.registryDeprecations[key]
+	m.registryDeprecationMutex.Unlock()
+	if ok {
+		return message, nil
+	}
+
+	registryPackage, err := registryClientFor(npmConfig, pkgConfig.Name).GetAbbreviatedPackage(pkgConfig.Name)
+	if err != nil {
+		return "", err
+	}
+	if version := registryPackage.Version(pkgConfig.Version); version != nil {
+		message = version.Deprecated
+	}
+
+	m.registryDeprecationMutex.Lock()
+	m.registryDeprecations[key] = message
+	m.registryDeprecationMutex.Unlock()
+
+	return message, nil
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependentsIndexDeprecations(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	writePackageTree(t, rootPath, map[string]string{
+		"node_modules/a":      `{"name": "a", "version": "1.0.0", "dependencies": {"old": "^1.0.0", "locked": "^1.0.0"}}`,
+		"node_modules/old":    `{"name": "old", "version": "1.0.0", "deprecated": "use new instead"}`,
+		"node_modules/locked": `{"name": "locked", "version": "2.0.0"}`,
+		"node_modules/stale":  `{"name": "stale", "version": "2.0.0"}`,
+		"node_modules/b":      `{"name": "b", "version": "1.0.0", "dependencies": {"old": "^1.0.0"}}`,
+		"node_modules/stray":  `{"name": "stray", "version": "1.0.0", "deprecated": "nobody uses this"}`,
+	})
+
+	pkg := &Package{
+		Path: rootPath,
+		Config: PackageConfig{
+			Dependencies:    map[string]string{"a": "^1.0.0", "stale": "^2.0.0"},
+			DevDependencies: map[string]string{"b": "^1.0.0"},
+		},
+	}
+	index := NewDummyNpmManager().BuildDependentsIndex(pkg)
+
+	lockfile := &Lockfile{
+		Path: filepath.Join(rootPath, "package-lock.json"),
+		Packages: map[string]*LockedPackage{
+			"node_modules/locked": {Version: "2.0.0", Deprecated: "this version is broken"},
+			// the lockfile is behind what's installed
+			"node_modules/stale": {Version: "1.0.0", Deprecated: "1.x is unsupported"},
+		},
+	}
+
+	type deprecation struct {
+		name    string
+		message string
+		via     []string
+	}
+	summarise := func(deprecations []*DeprecatedPackage) []deprecation {
+		result := make([]deprecation, len(deprecations))
+		for i, d := range deprecations {
+			result[i] = deprecation{name: d.Name, message: d.Message, via: d.Via}
+		}
+		return result
+	}
+
+	assert.EqualValues(t, []deprecation{
+		{name: "locked", message: "this version is broken", via: []string{"a"}},
+		{name: "old", message: "use new instead", via: []string{"a", "b"}},
+	}, summarise(index.Deprecations(lockfile, nil)))
+
+	deprecations := index.Deprecations(nil, map[string]string{filepath.Join(rootPath, "node_modules", "a"): "a is no more"})
+	assert.EqualValues(t, []deprecation{
+		{name: "a", message: "a is no more", via: []string{"a"}},
+		{name: "old", message: "use new instead", via: []string{"a", "b"}},
+	}, summarise(deprecations))
+	assert.EqualValues(t, filepath.Join("node_modules", "old"), deprecations[1].Path)
+}
+
+func TestGetRegistryDeprecations(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	writePackageTree(t, rootPath, map[string]string{
+		"node_modules/a": `{"name": "a", "version": "1.0.0"}`,
+		"node_modules/b": `{"name": "b", "version": "2.0.0"}`,
+	})
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if !strings.HasPrefix(r.Header.Get("Accept"), "application/vnd.npm.install-v1+json") {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		switch r.URL.Path {
+		case "/a":
+			fmt.Fprint(w, `{"name": "a", "versions": {"1.0.0": {"deprecated": "upgrade to 2.x"}, "2.0.0": {}}}`)
+		case "/b":
+			fmt.Fprint(w, `{"name": "b", "versions": {"1.0.0": {"deprecated": "upgrade to 2.x"}, "2.0.0": {}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	os.Setenv("npm_config_registry", server.URL)
+	defer os.Unsetenv("npm_config_registry")
+
+	pkg := &Package{
+		Path:   rootPath,
+		Config: PackageConfig{Dependencies: map[string]string{"a": "^1.0.0", "b": "^2.0.0", "missing": "^1.0.0"}},
+	}
+	manager := NewDummyNpmManager()
+	index := manager.BuildDependentsIndex(pkg)
+	expected := map[string]string{filepath.Join(rootPath, "node_modules", "a"): "upgrade to 2.x"}
+
+	assert.EqualValues(t, expected, manager.GetRegistryDeprecations(pkg, index))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&requests))
+
+	// we've already asked about these versions
+	assert.EqualValues(t, expected, manager.GetRegistryDeprecations(pkg, index))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&requests))
+}
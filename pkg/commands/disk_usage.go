@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// DiskUsage is how much disk space a direct dependency costs us
+type DiskUsage struct {
+	// Size covers the dependency itself and every package that only it pulls
+	// in, i.e. what we'd get back by removing it
+	Size int64
+	// SharedSize covers packages it pulls in which other direct dependencies
+	// also pull in
+	SharedSize int64
+	// Packages are those counted towards Size, largest first
+	Packages []PackageSize
+}
+
+// PackageSize is the size of an installed package, not including its own
+// node_modules folder
+type PackageSize struct {
+	Name    string
+	Version string
+	// Path is relative to the root package
+	Path string
+	Size int64
+}
+
+// DiskUsage works out the disk usage of each of the root package's direct
+// dependencies, keyed by name. Package sizes are read concurrently given big
+// node_modules folders can have tens of thousands of files.
+func (index *DependentsIndex) DiskUsage() map[string]*DiskUsage {
+	dependencies, directDeps := index.dependencyGraph()
+
+	reachable := map[string]map[string]bool{}
+	// how many direct dependencies pull each package in
+	reachCounts := map[string]int{}
+	for _, directDep := range directDeps {
+		if _, ok := reachable[directDep.name]; ok {
+			// a dependency may be listed under more than one kind
+			continue
+		}
+		visited := reachableFrom(dependencies, directDep.path)
+		reachable[directDep.name] = visited
+		for path := range visited {
+			reachCounts[path]++
+		}
+	}
+
+	paths := make([]string, 0, len(reachCounts))
+	for path := range reachCounts {
+		paths = append(paths, path)
+	}
+	sizes := packageSizes(paths)
+
+	usages := map[string]*DiskUsage{}
+	for name, visited := range reachable {
+		usage := &DiskUsage{}
+		for path := range visited {
+			if reachCounts[path] > 1 {
+				usage.SharedSize += sizes[path]
+				continue
+			}
+			usage.Size += sizes[path]
+			step := index.step(path)
+			usage.Packages = append(usage.Packages, PackageSize{Name: step.Name, Version: step.Version, Path: step.Path, Size: sizes[path]})
+		}
+		sort.Slice(usage.Packages, func(i, j int) bool {
+			if usage.Packages[i].Size != usage.Packages[j].Size {
+				return usage.Packages[i].Size > usage.Packages[j].Size
+			}
+			return usage.Packages[i].Path < usage.Packages[j].Path
+		})
+		usages[name] = usage
+	}
+
+	return usages
+}
+
+// packageSizes gets the size of each package directory using a pool of workers
+func packageSizes(paths []string) map[string]int64 {
+	sizes := make(map[string]int64, len(paths))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	pathChan := make(chan string)
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range pathChan {
+				size := packageSize(path)
+				mutex.Lock()
+				sizes[path] = size
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for _, path := range paths {
+		pathChan <- path
+	}
+	close(pathChan)
+	wg.Wait()
+
+	return sizes
+}
+
+// packageSize skips nested node_modules folders because the packages in there
+// are counted separately. Symlinks aren't followed, so linked packages cost
+// next to nothing.
+func packageSize(dir string) int64 {
+	var size int64
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() && path != dir && info.Name() == "node_modules" {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependentsIndexDiskUsage(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	// each package gets an index.js of the given size on top of its package.json
+	type installedPackage struct {
+		config   string
+		fileSize int
+	}
+	packages := map[string]installedPackage{
+		"node_modules/a":                {config: `{"name": "a", "version": "1.0.0", "dependencies": {"c": "^1.0.0", "d": "^1.0.0"}}`, fileSize: 100},
+		"node_modules/b":                {config: `{"name": "b", "version": "1.0.0", "dependencies": {"c": "^1.0.0", "e": "^1.0.0"}}`, fileSize: 200},
+		"node_modules/b/node_modules/e": {config: `{"name": "e", "version": "1.0.0"}`, fileSize: 1000},
+		"node_modules/c":                {config: `{"name": "c", "version": "1.0.0"}`, fileSize: 300},
+		"node_modules/d":                {config: `{"name": "d", "version": "1.0.0"}`, fileSize: 400},
+	}
+	configs := map[string]string{}
+	for path, installed := range packages {
+		configs[path] = installed.config
+	}
+	writePackageTree(t, rootPath, configs)
+
+	sizes := map[string]int64{}
+	for path, installed := range packages {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(rootPath, path, "index.js"), []byte(strings.Repeat("x", installed.fileSize)), 0644))
+		sizes[path] = int64(len(installed.config) + installed.fileSize)
+	}
+	pkg := &Package{
+		Path: rootPath,
+		Config: PackageConfig{
+			Dependencies:    map[string]string{"a": "^1.0.0"},
+			DevDependencies: map[string]string{"b": "^1.0.0"},
+		},
+	}
+
+	usages := NewDummyNpmManager().BuildDependentsIndex(pkg).DiskUsage()
+	assert.EqualValues(t, 2, len(usages))
+
+	// c is shared between a and b so it's not counted towards either
+	assert.EqualValues(t, sizes["node_modules/a"]+sizes["node_modules/d"], usages["a"].Size)
+	assert.EqualValues(t, sizes["node_modules/c"], usages["a"].SharedSize)
+	assert.EqualValues(t, []PackageSize{
+		{Name: "d", Version: "1.0.0", Path: filepath.FromSlash("node_modules/d"), Size: sizes["node_modules/d"]},
+		{Name: "a", Version: "1.0.0", Path: filepath.FromSlash("node_modules/a"), Size: sizes["node_modules/a"]},
+	}, usages["a"].Packages)
+
+	// b's own size doesn't include its nested node_modules folder
+	assert.EqualValues(t, sizes["node_modules/b"]+sizes["node_modules/b/node_modules/e"], usages["b"].Size)
+	assert.EqualValues(t, "e", usages["b"].Packages[0].Name)
+}
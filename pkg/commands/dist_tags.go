@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/jesseduffield/semver/v3"
+)
+
+// DistTagChange adds or moves a dist-tag, or removes it if Version is empty
+type DistTagChange struct {
+	Tag     string
+	Version string
+}
+
+// Apply returns the tags as they'll be after the change, leaving the given
+// map untouched
+func (c DistTagChange) Apply(tags map[string]string) map[string]string {
+	result := make(map[string]string, len(tags)+1)
+	for tag, version := range tags {
+		result[tag] = version
+	}
+	if c.Version == "" {
+		delete(result, c.Tag)
+	} else {
+		result[c.Tag] = c.Version
+	}
+	return result
+}
+
+// Command returns the command to make the change to the named package
+func (c DistTagChange) Command(pm PackageManager, name string) string {
+	if c.Version == "" {
+		return pm.DistTagRemove(name, c.Tag)
+	}
+	return pm.DistTagAdd(name, c.Version, c.Tag)
+}
+
+// Validate checks the change against what the registry would accept: the
+// version needs to have been published, 'latest' can't be removed, and tags
+// can't look like version ranges, otherwise `npm install pkg@tag` would be
+// ambiguous
+func (c DistTagChange) Validate(pkg *RegistryPackage) error {
+	tag := strings.TrimSpace(c.Tag)
+	if tag == "" {
+		return errors.New("tag cannot be blank")
+	}
+	if strings.ContainsAny(tag, " \t/@") {
+		return errors.New("tag cannot contain spaces, slashes, or '@'")
+	}
+	if _, err := semver.NewConstraint(tag); err == nil {
+		return errors.New("tag cannot be a valid version range: " + tag)
+	}
+
+	if c.Version == "" {
+		if tag == "latest" {
+			return errors.New("the latest tag cannot be removed")
+		}
+		if _, ok := pkg.DistTags[tag]; !ok {
+			return errors.New(tag + " is not a tag of " + pkg.Name)
+		}
+		return nil
+	}
+
+	if pkg.Version(c.Version) == nil {
+		return errors.New(pkg.Name + "@" + c.Version + " has not been published")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistTagChange(t *testing.T) {
+	registryPackage := &RegistryPackage{
+		Name:     "foo",
+		DistTags: map[string]string{"latest": "1.0.0", "next": "2.0.0-beta.0"},
+		Versions: []*RegistryVersion{{Version: "2.0.0-beta.0"}, {Version: "1.0.0"}},
+	}
+
+	type scenario struct {
+		name          string
+		change        DistTagChange
+		expectedTags  map[string]string
+		expectedError string
+	}
+
+	scenarios := []scenario{
+		{
+			name:         "add",
+			change:       DistTagChange{Tag: "beta", Version: "2.0.0-beta.0"},
+			expectedTags: map[string]string{"latest": "1.0.0", "next": "2.0.0-beta.0", "beta": "2.0.0-beta.0"},
+		},
+		{
+			name:         "move",
+			change:       DistTagChange{Tag: "next", Version: "1.0.0"},
+			expectedTags: map[string]string{"latest": "1.0.0", "next": "1.0.0"},
+		},
+		{
+			name:         "remove",
+			change:       DistTagChange{Tag: "next"},
+			expectedTags: map[string]string{"latest": "1.0.0"},
+		},
+		{
+			name:          "remove latest",
+			change:        DistTagChange{Tag: "latest"},
+			expectedTags:  map[string]string{"next": "2.0.0-beta.0"},
+			expectedError: "the latest tag cannot be removed",
+		},
+		{
+			name:          "remove missing tag",
+			change:        DistTagChange{Tag: "beta"},
+			expectedTags:  map[string]string{"latest": "1.0.0", "next": "2.0.0-beta.0"},
+			expectedError: "beta is not a tag of foo",
+		},
+		{
+			name:          "unpublished version",
+			change:        DistTagChange{Tag: "next", Version: "3.0.0"},
+			expectedTags:  map[string]string{"latest": "1.0.0", "next": "3.0.0"},
+			expectedError: "foo@3.0.0 has not been published",
+		},
+		{
+			name:          "tag that looks like a range",
+			change:        DistTagChange{Tag: "v1", Version: "1.0.0"},
+			expectedTags:  map[string]string{"latest": "1.0.0", "next": "2.0.0-beta.0", "v1": "1.0.0"},
+			expectedError: "tag cannot be a valid version range: v1",
+		},
+		{
+			name:          "tag with a space",
+			change:        DistTagChange{Tag: "my tag", Version: "1.0.0"},
+			expectedTags:  map[string]string{"latest": "1.0.0", "next": "2.0.0-beta.0", "my tag": "1.0.0"},
+			expectedError: "tag cannot contain spaces, slashes, or '@'",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := s.change.Validate(registryPackage)
+			if s.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, s.expectedError)
+			}
+			assert.EqualValues(t, s.expectedTags, s.change.Apply(registryPackage.DistTags))
+		})
+	}
+
+	// the original tags are left alone
+	assert.EqualValues(t, map[string]string{"latest": "1.0.0", "next": "2.0.0-beta.0"}, registryPackage.DistTags)
+
+	assert.EqualValues(t, "npm dist-tag add foo@1.0.0 next", DistTagChange{Tag: "next", Version: "1.0.0"}.Command(&Npm{}, "foo"))
+	assert.EqualValues(t, "yarn tag remove foo next", DistTagChange{Tag: "next"}.Command(&Yarn{}, "foo"))
+}
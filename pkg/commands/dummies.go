@@ -27,6 +27,7 @@ func NewDummyAppConfig() *config.AppConfig {
 		Debug:       false,
 		BuildSource: "",
 		UserConfig:  viper.New(),
+		AppState:    &config.AppState{},
 	}
 	_ = yaml.Unmarshal([]byte{}, appConfig.AppState)
 	return appConfig
@@ -47,9 +48,16 @@ func NewDummyNpmManager() *NpmManager {
 // NewDummyNpmManagerWithOSCommand creates a new dummy NpmManager for testing
 func NewDummyNpmManagerWithOSCommand(osCommand *OSCommand) *NpmManager {
 	return &NpmManager{
-		Log:       NewDummyLog(),
-		OSCommand: osCommand,
-		Tr:        i18n.NewLocalizer(NewDummyLog()),
-		Config:    NewDummyAppConfig(),
+		Log:             NewDummyLog(),
+		OSCommand:       osCommand,
+		Tr:              i18n.NewLocalizer(NewDummyLog()),
+		Config:          NewDummyAppConfig(),
+		globalLinkDirs:  map[string]string{},
+		lockfiles:       map[string]*Lockfile{},
+		packageConfigs:  map[string]*PackageConfig{},
+		tarballContents: map[string]*TarballContents{},
+		tarballs:        map[string]*tarballHashes{},
+
+		registryDeprecations: map[string]string{},
 	}
 }
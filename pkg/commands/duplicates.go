@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+	"github.com/jesseduffield/semver/v3"
+)
+
+// DuplicatePackage is a package that's installed at more than one version
+type DuplicatePackage struct {
+	Name string
+	// Versions are sorted from lowest to highest
+	Versions []*DuplicateVersion
+}
+
+type DuplicateVersion struct {
+	Version string
+	// Paths are relative to the root package
+	Paths []string
+	// DirectDependencies are the root package's dependencies which pull in
+	// this version
+	DirectDependencies []string
+}
+
+// Duplicates groups installed packages by name, returning those which are
+// installed at more than one version, with the most duplicated first
+func (index *DependentsIndex) Duplicates() []*DuplicatePackage {
+	// which of the root package's dependencies pull in each installed package
+	dependencies, directDeps := index.dependencyGraph()
+	directDepsByPath := map[string][]string{}
+	for _, directDep := range directDeps {
+		for path := range reachableFrom(dependencies, directDep.path) {
+			if !utils.IncludesString(directDepsByPath[path], directDep.name) {
+				directDepsByPath[path] = append(directDepsByPath[path], directDep.name)
+			}
+		}
+	}
+
+	versionsByName := map[string]map[string]*DuplicateVersion{}
+	for path, pkgConfig := range index.packages {
+		if pkgConfig.Name == "" || pkgConfig.Version == "" {
+			continue
+		}
+		if versionsByName[pkgConfig.Name] == nil {
+			versionsByName[pkgConfig.Name] = map[string]*DuplicateVersion{}
+		}
+		version := versionsByName[pkgConfig.Name][pkgConfig.Version]
+		if version == nil {
+			version = &DuplicateVersion{Version: pkgConfig.Version}
+			versionsByName[pkgConfig.Name][pkgConfig.Version] = version
+		}
+
+		relPath, err := filepath.Rel(index.rootPath, path)
+		if err != nil {
+			relPath = path
+		}
+		version.Paths = append(version.Paths, relPath)
+
+		for _, name := range directDepsByPath[path] {
+			if !utils.IncludesString(version.DirectDependencies, name) {
+				version.DirectDependencies = append(version.DirectDependencies, name)
+			}
+		}
+	}
+
+	duplicates := []*DuplicatePackage{}
+	for name, versions := range versionsByName {
+		if len(versions) < 2 {
+			continue
+		}
+		duplicate := &DuplicatePackage{Name: name}
+		for _, version := range versions {
+			sort.Strings(version.Paths)
+			sort.Strings(version.DirectDependencies)
+			duplicate.Versions = append(duplicate.Versions, version)
+		}
+		sort.Slice(duplicate.Versions, func(i, j int) bool {
+			return compareVersions(duplicate.Versions[i].Version, duplicate.Versions[j].Version) < 0
+		})
+		duplicates = append(duplicates, duplicate)
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		if len(duplicates[i].Versions) != len(duplicates[j].Versions) {
+			return len(duplicates[i].Versions) > len(duplicates[j].Versions)
+		}
+		return duplicates[i].Name < duplicates[j].Name
+	})
+
+	return duplicates
+}
+
+// compareVersions falls back to comparing strings if either version isn't valid semver
+func compareVersions(a string, b string) int {
+	versionA, errA := semver.NewVersion(a)
+	versionB, errB := semver.NewVersion(b)
+	if errA != nil || errB != nil {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	}
+	return versionA.Compare(versionB)
+}
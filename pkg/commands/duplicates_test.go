@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependentsIndexDuplicates(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	writePackageTree(t, rootPath, map[string]string{
+		"node_modules/a":                   `{"name": "a", "version": "1.0.0", "dependencies": {"c": "^1.0.0"}}`,
+		"node_modules/b":                   `{"name": "b", "version": "1.0.0", "dependencies": {"c": "^10.0.0"}}`,
+		"node_modules/b/node_modules/c":    `{"name": "c", "version": "10.0.0"}`,
+		"node_modules/d":                   `{"name": "d", "version": "1.0.0", "dependencies": {"c": "^2.0.0"}}`,
+		"node_modules/d/node_modules/c":    `{"name": "c", "version": "2.0.0"}`,
+		"node_modules/c":                   `{"name": "c", "version": "1.0.0"}`,
+		"node_modules/e":                   `{"name": "e", "version": "1.0.0", "dependencies": {"d": "^1.0.0"}}`,
+		"node_modules/unique":              `{"name": "unique", "version": "1.0.0"}`,
+		"node_modules/e/node_modules/what": `{"name": "unique", "version": "2.0.0"}`,
+	})
+
+	pkg := &Package{
+		Path: rootPath,
+		Config: PackageConfig{
+			Dependencies: map[string]string{"a": "^1.0.0", "b": "^1.0.0", "c": "^1.0.0", "e": "^1.0.0", "unique": "^1.0.0"},
+		},
+	}
+
+	duplicates := NewDummyNpmManager().BuildDependentsIndex(pkg).Duplicates()
+
+	assert.EqualValues(t, []*DuplicatePackage{
+		{
+			Name: "c",
+			Versions: []*DuplicateVersion{
+				{Version: "1.0.0", Paths: []string{filepath.FromSlash("node_modules/c")}, DirectDependencies: []string{"a", "c"}},
+				{Version: "2.0.0", Paths: []string{filepath.FromSlash("node_modules/d/node_modules/c")}, DirectDependencies: []string{"e"}},
+				{Version: "10.0.0", Paths: []string{filepath.FromSlash("node_modules/b/node_modules/c")}, DirectDependencies: []string{"b"}},
+			},
+		},
+		{
+			Name: "unique",
+			Versions: []*DuplicateVersion{
+				{Version: "1.0.0", Paths: []string{filepath.FromSlash("node_modules/unique")}, DirectDependencies: []string{"unique"}},
+				{Version: "2.0.0", Paths: []string{filepath.FromSlash("node_modules/e/node_modules/what")}},
+			},
+		},
+	}, duplicates)
+}
+
+func TestDependentsIndexDuplicatesManyChains(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	// c is reached through p and q, each of which has well over maxWhyChains
+	// chains leading up to the root
+	configs := map[string]string{
+		"node_modules/c":                `{"name": "c", "version": "1.0.0"}`,
+		"node_modules/p":                `{"name": "p", "version": "1.0.0", "dependencies": {"c": "^1.0.0"}}`,
+		"node_modules/q":                `{"name": "q", "version": "1.0.0", "dependencies": {"c": "^1.0.0"}}`,
+		"node_modules/x":                `{"name": "x", "version": "1.0.0", "dependencies": {"c": "^2.0.0"}}`,
+		"node_modules/x/node_modules/c": `{"name": "c", "version": "2.0.0"}`,
+	}
+	rootDeps := map[string]string{"x": "^1.0.0"}
+	expectedDirectDeps := []string{}
+	for _, side := range []struct{ middle, top, target string }{{"m", "a", "p"}, {"n", "b", "q"}} {
+		middleDeps := []string{}
+		for i := 0; i <= 10; i++ {
+			name := fmt.Sprintf("%s%02d", side.middle, i)
+			configs["node_modules/"+name] = fmt.Sprintf(`{"name": "%s", "version": "1.0.0", "dependencies": {"%s": "^1.0.0"}}`, name, side.target)
+			middleDeps = append(middleDeps, fmt.Sprintf(`"%s": "^1.0.0"`, name))
+		}
+		for i := 0; i < 10; i++ {
+			name := fmt.Sprintf("%s%d", side.top, i)
+			configs["node_modules/"+name] = fmt.Sprintf(`{"name": "%s", "version": "1.0.0", "dependencies": {%s}}`, name, strings.Join(middleDeps, ", "))
+			rootDeps[name] = "^1.0.0"
+			expectedDirectDeps = append(expectedDirectDeps, name)
+		}
+	}
+	writePackageTree(t, rootPath, configs)
+
+	pkg := &Package{Path: rootPath, Config: PackageConfig{Dependencies: rootDeps}}
+
+	duplicates := NewDummyNpmManager().BuildDependentsIndex(pkg).Duplicates()
+
+	assert.EqualValues(t, 1, len(duplicates))
+	assert.EqualValues(t, "c", duplicates[0].Name)
+	assert.EqualValues(t, expectedDirectDeps, duplicates[0].Versions[0].DirectDependencies)
+	assert.EqualValues(t, []string{"x"}, duplicates[0].Versions[1].DirectDependencies)
+}
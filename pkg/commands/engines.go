@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jesseduffield/semver/v3"
+)
+
+// EngineVersions are the versions of node and npm on the user's machine.
+// Either is empty if we couldn't find it
+type EngineVersions struct {
+	Node string
+	Npm  string
+}
+
+// EngineViolation is a package whose `engines` field rules out the version of
+// node or npm that we're running
+type EngineViolation struct {
+	Name    string
+	Version string
+	// Path is the package's directory
+	Path string
+	// Engine is either 'node' or 'npm'
+	Engine     string
+	Constraint string
+	Installed  string
+}
+
+// GetEngineVersions asks node and npm for their versions, remembering the
+// answer given they won't change while we're running
+func (m *NpmManager) GetEngineVersions() *EngineVersions {
+	m.engineVersionsMutex.Lock()
+	defer m.engineVersionsMutex.Unlock()
+
+	if m.engineVersions != nil {
+		return m.engineVersions
+	}
+
+	versions := &EngineVersions{}
+	if output, err := m.OSCommand.RunCommandWithOutput("node --version"); err == nil {
+		versions.Node = strings.TrimPrefix(strings.TrimSpace(output), "v")
+	}
+	if output, err := m.OSCommand.RunCommandWithOutput("npm --version"); err == nil {
+		versions.Npm = strings.TrimSpace(output)
+	}
+	m.engineVersions = versions
+
+	return versions
+}
+
+// CheckEngines returns the engines the package isn't happy with. Constraints
+// we can't parse are given the benefit of the doubt
+func CheckEngines(pkgConfig *PackageConfig, path string, versions *EngineVersions) []*EngineViolation {
+	violations := []*EngineViolation{}
+	for _, engine := range []struct {
+		name       string
+		constraint string
+		installed  string
+	}{
+		{name: "node", constraint: pkgConfig.Engines.Node, installed: versions.Node},
+		{name: "npm", constraint: pkgConfig.Engines.Npm, installed: versions.Npm},
+	} {
+		if engine.constraint == "" || engine.installed == "" || satisfiesConstraint(engine.constraint, engine.installed) {
+			continue
+		}
+		violations = append(violations, &EngineViolation{
+			Name:       pkgConfig.Name,
+			Version:    pkgConfig.Version,
+			Path:       path,
+			Engine:     engine.name,
+			Constraint: engine.constraint,
+			Installed:  engine.installed,
+		})
+	}
+	return violations
+}
+
+// satisfiesConstraint gives constraints and versions we can't parse the benefit of the doubt
+func satisfiesConstraint(constraint string, installed string) bool {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "*" || constraint == "latest" {
+		return true
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return true
+	}
+	v, err := semver.NewVersion(installed)
+	if err != nil {
+		return true
+	}
+	return c.Check(v)
+}
+
+// EngineViolations checks the package and everything installed in its
+// node_modules against the versions of node and npm we're running. The
+// package's own violations come first, followed by its dependencies' sorted
+// by name.
+func (index *DependentsIndex) EngineViolations(pkg *Package, versions *EngineVersions) []*EngineViolation {
+	violations := CheckEngines(&pkg.Config, pkg.Path, versions)
+
+	depViolations := []*EngineViolation{}
+	for path, pkgConfig := range index.packages {
+		depViolations = append(depViolations, CheckEngines(pkgConfig, path, versions)...)
+	}
+	sort.Slice(depViolations, func(i, j int) bool {
+		a, b := depViolations[i], depViolations[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Engine < b.Engine
+	})
+
+	return append(violations, depViolations...)
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSatisfiesConstraint(t *testing.T) {
+	type scenario struct {
+		constraint string
+		installed  string
+		expected   bool
+	}
+
+	scenarios := []scenario{
+		{constraint: ">=10", installed: "12.16.1", expected: true},
+		{constraint: ">=14", installed: "12.16.1", expected: false},
+		{constraint: ">= 8.9.0", installed: "12.16.1", expected: true},
+		{constraint: ">=10 <12", installed: "12.16.1", expected: false},
+		{constraint: "^10 || ^12", installed: "12.16.1", expected: true},
+		{constraint: "0.10.x", installed: "12.16.1", expected: false},
+		{constraint: "*", installed: "12.16.1", expected: true},
+		{constraint: "not a constraint", installed: "12.16.1", expected: true},
+		{constraint: ">=6", installed: "not a version", expected: true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.constraint, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, satisfiesConstraint(s.constraint, s.installed))
+		})
+	}
+}
+
+func TestDependentsIndexEngineViolations(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	writePackageTree(t, rootPath, map[string]string{
+		"node_modules/a":                `{"name": "a", "version": "1.0.0", "engines": {"node": ">=14"}, "dependencies": {"c": "^1.0.0"}}`,
+		"node_modules/b":                `{"name": "b", "version": "1.0.0", "engines": {"node": ">=8", "npm": ">=5"}}`,
+		"node_modules/a/node_modules/c": `{"name": "c", "version": "1.0.0", "engines": {"npm": "<6"}}`,
+	})
+
+	pkg := &Package{
+		Path: rootPath,
+		Config: PackageConfig{
+			Name:         "root",
+			Version:      "0.1.0",
+			Dependencies: map[string]string{"a": "^1.0.0", "b": "^1.0.0"},
+		},
+	}
+	pkg.Config.Engines.Node = "^10"
+	versions := &EngineVersions{Node: "12.16.1", Npm: "6.13.4"}
+
+	index := NewDummyNpmManager().BuildDependentsIndex(pkg)
+	violations := index.EngineViolations(pkg, versions)
+
+	assert.EqualValues(t, []*EngineViolation{
+		{Name: "root", Version: "0.1.0", Path: rootPath, Engine: "node", Constraint: "^10", Installed: "12.16.1"},
+		{Name: "a", Version: "1.0.0", Path: filepath.Join(rootPath, "node_modules", "a"), Engine: "node", Constraint: ">=14", Installed: "12.16.1"},
+		{Name: "c", Version: "1.0.0", Path: filepath.Join(rootPath, "node_modules", "a", "node_modules", "c"), Engine: "npm", Constraint: "<6", Installed: "6.13.4"},
+	}, violations)
+
+	// without knowing the node version we can't say anything about node constraints
+	violations = index.EngineViolations(pkg, &EngineVersions{Npm: "5.0.0"})
+	assert.EqualValues(t, 0, len(violations))
+}
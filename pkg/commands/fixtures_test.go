@@ -0,0 +1,20 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writePackageTree writes a package.json with the given contents at each of
+// the given paths, which are relative to root
+func writePackageTree(t *testing.T, root string, configs map[string]string) {
+	for path, config := range configs {
+		dir := filepath.Join(root, filepath.FromSlash(path))
+		assert.NoError(t, os.MkdirAll(dir, 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "package.json"), []byte(config), 0644))
+	}
+}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GlobalPackage is a package installed in npm's global node_modules
+type GlobalPackage struct {
+	// Name is the name the package is installed under e.g. '@scope/name'
+	Name string
+	Path string
+	// LinkPath is where the package is symlinked to if it was installed via
+	// `npm link` or from a local folder
+	LinkPath string
+	// PackageConfig is nil if we couldn't read the package's package.json
+	PackageConfig *PackageConfig
+	Outdated      *OutdatedInfo
+}
+
+func (p *GlobalPackage) ID() string {
+	return fmt.Sprintf("global:%s", p.Path)
+}
+
+func (p *GlobalPackage) Linked() bool {
+	return p.LinkPath != ""
+}
+
+func (p *GlobalPackage) Version() string {
+	if p.PackageConfig == nil {
+		return ""
+	}
+	return p.PackageConfig.Version
+}
+
+// BinNames are the commands the package puts on the PATH, sorted
+func (p *GlobalPackage) BinNames() []string {
+	if p.PackageConfig == nil {
+		return nil
+	}
+	names := make([]string, 0, len(p.PackageConfig.Bin))
+	for name := range p.PackageConfig.Bin {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetGlobalPackages lists what's installed in npm's global root. We don't look
+// at yarn or pnpm's global folders given we manage these with `npm -g`
+func (m *NpmManager) GetGlobalPackages() ([]*GlobalPackage, error) {
+	if m.NpmRoot == "" {
+		return nil, nil
+	}
+	return m.readGlobalPackages(m.NpmRoot)
+}
+
+func (m *NpmManager) readGlobalPackages(dir string) ([]*GlobalPackage, error) {
+	fileInfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	pkgs := []*GlobalPackage{}
+	for _, fileInfo := range fileInfos {
+		name := fileInfo.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if strings.HasPrefix(name, "@") {
+			scopedPkgs, err := m.readGlobalPackages(path)
+			if err != nil {
+				return nil, err
+			}
+			for _, scopedPkg := range scopedPkgs {
+				scopedPkg.Name = name + "/" + scopedPkg.Name
+			}
+			pkgs = append(pkgs, scopedPkgs...)
+			continue
+		}
+
+		pkg := &GlobalPackage{Name: name, Path: path}
+		if fileInfo.Mode()&os.ModeSymlink == os.ModeSymlink {
+			linkPath, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				// swallowing error: a dangling link is still worth showing so
+				// that the user can uninstall it
+				m.Log.Error(err)
+				linkPath, _ = os.Readlink(path)
+			}
+			pkg.LinkPath = linkPath
+		}
+
+		pkgConfig, err := m.getPackageConfig(path)
+		if err != nil {
+			m.Log.Error(err)
+		} else {
+			pkg.PackageConfig = pkgConfig
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// GetGlobalOutdated asks npm which global packages are outdated
+func (m *NpmManager) GetGlobalOutdated() (map[string]*OutdatedInfo, error) {
+	// npm outdated exits with a non-zero code when anything is outdated, so we
+	// only care about the error if we can't parse the output
+	output, cmdErr := m.OSCommand.RunCommandWithOutput((&Npm{}).GlobalOutdated())
+	outdated, err := ParseOutdated(output, "")
+	if err != nil {
+		// npm's own error report is more useful than its exit code
+		if cmdErr != nil && !strings.Contains(output, "{") {
+			return nil, cmdErr
+		}
+		return nil, err
+	}
+	return outdated, nil
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGlobalPackages(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	npmRoot := filepath.Join(rootPath, "lib", "node_modules")
+	linkedPath := filepath.Join(rootPath, "linked")
+
+	writePackageTree(t, rootPath, map[string]string{
+		"lib/node_modules/npm":             `{"name": "npm", "version": "9.0.0", "bin": {"npm": "bin/npm-cli.js", "npx": "bin/npx-cli.js"}}`,
+		"lib/node_modules/@scope/tool":     `{"name": "@scope/tool", "version": "1.0.0", "bin": "cli.js"}`,
+		"linked":                           `{"name": "linked", "version": "0.1.0"}`,
+		"lib/node_modules/.hidden-install": `{"name": "hidden", "version": "1.0.0"}`,
+	})
+	assert.NoError(t, os.Symlink(linkedPath, filepath.Join(npmRoot, "linked")))
+	// a half-finished install with no package.json
+	assert.NoError(t, os.MkdirAll(filepath.Join(npmRoot, "broken"), 0755))
+
+	manager := NewDummyNpmManager()
+	manager.NpmRoot = npmRoot
+	pkgs, err := manager.GetGlobalPackages()
+	assert.NoError(t, err)
+
+	type globalPackage struct {
+		name     string
+		version  string
+		linkPath string
+		binNames []string
+	}
+	result := []globalPackage{}
+	for _, pkg := range pkgs {
+		result = append(result, globalPackage{name: pkg.Name, version: pkg.Version(), linkPath: pkg.LinkPath, binNames: pkg.BinNames()})
+	}
+
+	expectedLinkPath, err := filepath.EvalSymlinks(linkedPath)
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, []globalPackage{
+		{name: "@scope/tool", version: "1.0.0", binNames: []string{"tool"}},
+		{name: "broken", binNames: nil},
+		{name: "linked", version: "0.1.0", linkPath: expectedLinkPath, binNames: []string{}},
+		{name: "npm", version: "9.0.0", binNames: []string{"npm", "npx"}},
+	}, result)
+}
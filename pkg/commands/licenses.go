@@ -0,0 +1,356 @@
+package commands
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// LicensePolicy says which licenses we're happy to depend on. Entries are
+// SPDX identifiers and may contain wildcards e.g. 'GPL-*'. Matching is case
+// insensitive.
+type LicensePolicy struct {
+	// Allow is the list of allowed licenses. If empty, any license not denied is allowed
+	Allow []string
+	// Deny takes precedence over Allow
+	Deny []string
+	// CheckDev is true if dev dependencies need to follow the policy too
+	CheckDev bool
+}
+
+// Check returns an explanation if the license expression breaks the policy.
+// With an OR expression we only need one of the licenses to be acceptable,
+// whereas with AND we need all of them to be. An expression we can't parse is
+// treated as a single license, so that e.g. 'Public Domain' can still be
+// listed in the policy.
+func (p *LicensePolicy) Check(license string) string {
+	if len(p.Allow) == 0 && len(p.Deny) == 0 {
+		return ""
+	}
+	license = strings.TrimSpace(license)
+	if license == "" {
+		if len(p.Allow) > 0 {
+			return "no license"
+		}
+		return ""
+	}
+
+	expression, err := parseLicenseExpression(license)
+	if err != nil {
+		expression = &licenseExpression{license: license}
+	}
+	return p.check(expression)
+}
+
+func (p *LicensePolicy) check(expression *licenseExpression) string {
+	switch expression.op {
+	case "OR":
+		left := p.check(expression.left)
+		if left == "" {
+			return ""
+		}
+		right := p.check(expression.right)
+		if right == "" {
+			return ""
+		}
+		return left + "; " + right
+	case "AND":
+		if left := p.check(expression.left); left != "" {
+			return left
+		}
+		return p.check(expression.right)
+	}
+
+	names := []string{expression.license}
+	if expression.exception != "" {
+		// the policy may mention a license along with a specific exception
+		names = []string{expression.license + " WITH " + expression.exception, expression.license}
+	}
+	for _, name := range names {
+		if matchesLicense(p.Deny, name) {
+			return name + " is denied"
+		}
+	}
+	if len(p.Allow) == 0 {
+		return ""
+	}
+	for _, name := range names {
+		if matchesLicense(p.Allow, name) {
+			return ""
+		}
+	}
+	return names[0] + " is not allowed"
+}
+
+func matchesLicense(patterns []string, license string) bool {
+	license = strings.ToLower(license)
+	for _, pattern := range patterns {
+		if matched, err := path.Match(strings.ToLower(strings.TrimSpace(pattern)), license); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// licenseExpression is a parsed SPDX license expression like
+// '(MIT OR Apache-2.0) AND GPL-2.0-only WITH Classpath-exception-2.0'
+type licenseExpression struct {
+	// op is 'AND' or 'OR' for a compound expression, or empty for a single license
+	op    string
+	left  *licenseExpression
+	right *licenseExpression
+
+	license   string
+	exception string
+}
+
+func parseLicenseExpression(str string) (*licenseExpression, error) {
+	parser := &licenseParser{tokens: tokenizeLicenseExpression(str)}
+	expression, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos != len(parser.tokens) {
+		return nil, errors.New("unexpected '" + parser.tokens[parser.pos] + "' in license expression")
+	}
+	return expression, nil
+}
+
+func tokenizeLicenseExpression(str string) []string {
+	str = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(str)
+	tokens := strings.Fields(str)
+	for i, token := range tokens {
+		// operators are meant to be upper case but plenty of packages don't bother
+		switch upper := strings.ToUpper(token); upper {
+		case "AND", "OR", "WITH":
+			tokens[i] = upper
+		}
+	}
+	return tokens
+}
+
+// licenseParser is a recursive descent parser. WITH binds tightest, followed
+// by AND, then OR.
+type licenseParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *licenseParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *licenseParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *licenseParser) parseOr() (*licenseExpression, error) {
+	return p.parseBinary("OR", p.parseAnd)
+}
+
+func (p *licenseParser) parseAnd() (*licenseExpression, error) {
+	return p.parseBinary("AND", p.parseWith)
+}
+
+func (p *licenseParser) parseBinary(op string, parseOperand func() (*licenseExpression, error)) (*licenseExpression, error) {
+	left, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == op {
+		p.next()
+		right, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		left = &licenseExpression{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *licenseParser) parseWith() (*licenseExpression, error) {
+	token := p.next()
+	switch token {
+	case "":
+		return nil, errors.New("unexpected end of license expression")
+	case "(":
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New("expected ')' in license expression")
+		}
+		return expression, nil
+	case ")", "AND", "OR", "WITH":
+		return nil, errors.New("unexpected '" + token + "' in license expression")
+	}
+
+	expression := &licenseExpression{license: token}
+	if p.peek() == "WITH" {
+		p.next()
+		exception := p.next()
+		if exception == "" || exception == "(" || exception == ")" {
+			return nil, errors.New("expected an exception after WITH in license expression")
+		}
+		expression.exception = exception
+	}
+	return expression, nil
+}
+
+// LicensedPackage is an installed package along with its license
+type LicensedPackage struct {
+	Name    string
+	Version string
+	// Path is relative to the root package
+	Path    string
+	License string
+}
+
+// LicenseGroup is every installed package with a given license
+type LicenseGroup struct {
+	// License is empty for packages with no license
+	License  string
+	Packages []*LicensedPackage
+	// Violation explains how the license breaks the policy, if it does
+	Violation string
+}
+
+// LicenseReport groups installed packages by license. A package that both
+// prod and dev dependencies pull in counts as a prod dependency
+type LicenseReport struct {
+	Prod []*LicenseGroup
+	Dev  []*LicenseGroup
+	// Violations maps each direct dependency's name to the packages it pulls
+	// in (including itself) whose license breaks the policy
+	Violations map[string][]*LicensedPackage
+}
+
+// Licenses reports on the licenses of everything installed, checking each one
+// against the given policy
+func (index *DependentsIndex) Licenses(policy *LicensePolicy) *LicenseReport {
+	dependencies, directDeps := index.dependencyGraph()
+
+	prod := map[string]bool{}
+	dev := map[string]bool{}
+	for _, directDep := range directDeps {
+		target := prod
+		if directDep.kind == "dev" {
+			target = dev
+		}
+		for path := range reachableFrom(dependencies, directDep.path) {
+			target[path] = true
+		}
+	}
+
+	violations := map[string]string{}
+	packages := map[string]*LicensedPackage{}
+	prodPackages := []*LicensedPackage{}
+	devPackages := []*LicensedPackage{}
+	for path, pkgConfig := range index.packages {
+		if !prod[path] && !dev[path] {
+			// extraneous
+			continue
+		}
+		relPath, err := filepath.Rel(index.rootPath, path)
+		if err != nil {
+			relPath = path
+		}
+		licensedPackage := &LicensedPackage{Name: pkgConfig.Name, Version: pkgConfig.Version, Path: relPath, License: strings.TrimSpace(pkgConfig.License)}
+		packages[path] = licensedPackage
+		if _, ok := violations[licensedPackage.License]; !ok {
+			violations[licensedPackage.License] = policy.Check(licensedPackage.License)
+		}
+		if prod[path] {
+			prodPackages = append(prodPackages, licensedPackage)
+		} else {
+			devPackages = append(devPackages, licensedPackage)
+		}
+	}
+
+	report := &LicenseReport{
+		Prod:       groupByLicense(prodPackages, violations),
+		Dev:        groupByLicense(devPackages, violations),
+		Violations: map[string][]*LicensedPackage{},
+	}
+	if !policy.CheckDev {
+		for _, group := range report.Dev {
+			group.Violation = ""
+		}
+	}
+
+	for _, directDep := range directDeps {
+		if directDep.kind == "dev" && !policy.CheckDev {
+			continue
+		}
+		for path := range reachableFrom(dependencies, directDep.path) {
+			licensedPackage := packages[path]
+			if licensedPackage == nil || violations[licensedPackage.License] == "" {
+				continue
+			}
+			report.Violations[directDep.name] = appendUniquePackage(report.Violations[directDep.name], licensedPackage)
+		}
+	}
+	for _, packages := range report.Violations {
+		sortLicensedPackages(packages)
+	}
+
+	return report
+}
+
+func appendUniquePackage(packages []*LicensedPackage, licensedPackage *LicensedPackage) []*LicensedPackage {
+	for _, existing := range packages {
+		if existing == licensedPackage {
+			return packages
+		}
+	}
+	return append(packages, licensedPackage)
+}
+
+// groupByLicense puts the groups that break the policy first, followed by the
+// most common licenses
+func groupByLicense(packages []*LicensedPackage, violations map[string]string) []*LicenseGroup {
+	groupMap := map[string]*LicenseGroup{}
+	groups := []*LicenseGroup{}
+	for _, licensedPackage := range packages {
+		group := groupMap[licensedPackage.License]
+		if group == nil {
+			group = &LicenseGroup{License: licensedPackage.License, Violation: violations[licensedPackage.License]}
+			groupMap[licensedPackage.License] = group
+			groups = append(groups, group)
+		}
+		group.Packages = append(group.Packages, licensedPackage)
+	}
+
+	for _, group := range groups {
+		sortLicensedPackages(group.Packages)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if (a.Violation != "") != (b.Violation != "") {
+			return a.Violation != ""
+		}
+		if len(a.Packages) != len(b.Packages) {
+			return len(a.Packages) > len(b.Packages)
+		}
+		return a.License < b.License
+	})
+	return groups
+}
+
+func sortLicensedPackages(packages []*LicensedPackage) {
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
+		}
+		return packages[i].Path < packages[j].Path
+	})
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLicensePolicyCheck(t *testing.T) {
+	type scenario struct {
+		name     string
+		policy   LicensePolicy
+		license  string
+		expected string
+	}
+
+	denyGPL := LicensePolicy{Deny: []string{"GPL-*", "AGPL-*"}}
+	allowPermissive := LicensePolicy{Allow: []string{"MIT", "ISC", "Apache-2.0", "BSD-*"}, Deny: []string{"GPL-*"}}
+
+	scenarios := []scenario{
+		{name: "no policy", policy: LicensePolicy{}, license: "GPL-3.0-only", expected: ""},
+		{name: "denied", policy: denyGPL, license: "GPL-3.0-only", expected: "GPL-3.0-only is denied"},
+		{name: "case insensitive", policy: denyGPL, license: "gpl-2.0", expected: "gpl-2.0 is denied"},
+		{name: "wildcard is anchored", policy: denyGPL, license: "LGPL-2.1", expected: ""},
+		{name: "or with an acceptable option", policy: denyGPL, license: "(GPL-2.0 OR MIT)", expected: ""},
+		{name: "or with no acceptable option", policy: denyGPL, license: "GPL-2.0 OR AGPL-3.0", expected: "GPL-2.0 is denied; AGPL-3.0 is denied"},
+		{name: "and", policy: denyGPL, license: "MIT AND GPL-2.0", expected: "GPL-2.0 is denied"},
+		{name: "lower case operators", policy: denyGPL, license: "mit and gpl-2.0", expected: "gpl-2.0 is denied"},
+		{name: "and binds tighter than or", policy: denyGPL, license: "MIT OR GPL-2.0 AND ISC", expected: ""},
+		{name: "nested parens", policy: denyGPL, license: "(MIT OR GPL-2.0) AND (GPL-3.0 OR AGPL-3.0)", expected: "GPL-3.0 is denied; AGPL-3.0 is denied"},
+		{name: "with exception", policy: denyGPL, license: "GPL-2.0-only WITH Classpath-exception-2.0", expected: "GPL-2.0-only WITH Classpath-exception-2.0 is denied"},
+		{name: "exception explicitly allowed", policy: LicensePolicy{Allow: []string{"MIT", "GPL-2.0-only WITH Classpath-exception-2.0"}}, license: "GPL-2.0-only WITH Classpath-exception-2.0", expected: ""},
+		{name: "allowed", policy: allowPermissive, license: "BSD-3-Clause", expected: ""},
+		{name: "not allowed", policy: allowPermissive, license: "MPL-2.0", expected: "MPL-2.0 is not allowed"},
+		{name: "deny beats allow", policy: LicensePolicy{Allow: []string{"*"}, Deny: []string{"GPL-*"}}, license: "GPL-3.0", expected: "GPL-3.0 is denied"},
+		{name: "missing license with allow list", policy: allowPermissive, license: "", expected: "no license"},
+		{name: "missing license with deny list", policy: denyGPL, license: "", expected: ""},
+		{name: "unparseable license", policy: LicensePolicy{Allow: []string{"Public Domain"}}, license: "Public Domain", expected: ""},
+		{name: "unbalanced parens", policy: allowPermissive, license: "(MIT OR ISC", expected: "(MIT OR ISC is not allowed"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, s.policy.Check(s.license))
+		})
+	}
+}
+
+func TestDependentsIndexLicenses(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	writePackageTree(t, rootPath, map[string]string{
+		"node_modules/a":      `{"name": "a", "version": "1.0.0", "license": "MIT", "dependencies": {"gpl": "^1.0.0", "shared": "^1.0.0"}}`,
+		"node_modules/gpl":    `{"name": "gpl", "version": "1.0.0", "license": "GPL-3.0"}`,
+		"node_modules/shared": `{"name": "shared", "version": "1.0.0", "license": "MIT"}`,
+		"node_modules/tool":   `{"name": "tool", "version": "1.0.0", "license": "AGPL-3.0", "dependencies": {"shared": "^1.0.0"}}`,
+		"node_modules/stray":  `{"name": "stray", "version": "1.0.0", "license": "GPL-3.0"}`,
+	})
+
+	pkg := &Package{
+		Path: rootPath,
+		Config: PackageConfig{
+			Dependencies:    map[string]string{"a": "^1.0.0"},
+			DevDependencies: map[string]string{"tool": "^1.0.0"},
+		},
+	}
+	index := NewDummyNpmManager().BuildDependentsIndex(pkg)
+
+	type group struct {
+		license   string
+		names     []string
+		violation string
+	}
+	groups := func(licenseGroups []*LicenseGroup) []group {
+		result := make([]group, len(licenseGroups))
+		for i, licenseGroup := range licenseGroups {
+			names := make([]string, len(licenseGroup.Packages))
+			for j, licensedPackage := range licenseGroup.Packages {
+				names[j] = licensedPackage.Name
+			}
+			result[i] = group{license: licenseGroup.License, names: names, violation: licenseGroup.Violation}
+		}
+		return result
+	}
+
+	report := index.Licenses(&LicensePolicy{Deny: []string{"GPL-*", "AGPL-*"}})
+
+	// shared is pulled in by both, so it counts as a prod dependency. stray
+	// isn't depended on by anything so it's left out
+	assert.EqualValues(t, []group{
+		{license: "GPL-3.0", names: []string{"gpl"}, violation: "GPL-3.0 is denied"},
+		{license: "MIT", names: []string{"a", "shared"}},
+	}, groups(report.Prod))
+	assert.EqualValues(t, []group{
+		{license: "AGPL-3.0", names: []string{"tool"}},
+	}, groups(report.Dev))
+	assert.EqualValues(t, 1, len(report.Violations))
+	assert.EqualValues(t, "gpl", report.Violations["a"][0].Name)
+	assert.EqualValues(t, filepath.Join("node_modules", "gpl"), report.Violations["a"][0].Path)
+
+	report = index.Licenses(&LicensePolicy{Deny: []string{"GPL-*", "AGPL-*"}, CheckDev: true})
+	assert.EqualValues(t, []group{
+		{license: "AGPL-3.0", names: []string{"tool"}, violation: "AGPL-3.0 is denied"},
+	}, groups(report.Dev))
+	assert.EqualValues(t, 2, len(report.Violations))
+	assert.EqualValues(t, "tool", report.Violations["tool"][0].Name)
+}
@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/jesseduffield/semver/v3"
+)
+
+// LockedPackage is an entry in a package-lock.json or npm-shrinkwrap.json file
+type LockedPackage struct {
+	Version    string
+	Resolved   string
+	Integrity  string
+	Deprecated string
+	Dev        bool
+	Optional   bool
+	// true if this entry is a symlink to a package elsewhere (e.g. a workspace member)
+	Link bool
+}
+
+type Lockfile struct {
+	// this is the sha256 of the lockfile
+	Sha             []byte
+	Path            string
+	LockfileVersion int64
+	// packages are keyed by their install location relative to the lockfile's
+	// directory, e.g. 'node_modules/a/node_modules/b'
+	Packages map[string]*LockedPackage
+}
+
+// Lookup finds the locked package that a package at the given path would use
+// when requiring the named dependency, mirroring how node resolves modules.
+// dir should be relative to the lockfile's directory ("" for the root package)
+func (l *Lockfile) Lookup(dir string, name string) *LockedPackage {
+	for {
+		if lockedPkg, ok := l.Packages[filepath.ToSlash(filepath.Join(dir, "node_modules", name))]; ok {
+			return lockedPkg
+		}
+		if dir == "" || dir == "." {
+			return nil
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// Versions returns every version of the named package in the lockfile, newest
+// first, given the same package can be installed at several versions
+func (l *Lockfile) Versions(name string) []string {
+	seen := map[string]bool{}
+	versions := []*semver.Version{}
+	for path, lockedPkg := range l.Packages {
+		if path != "node_modules/"+name && !strings.HasSuffix(path, "/node_modules/"+name) {
+			continue
+		}
+		if lockedPkg.Link || seen[lockedPkg.Version] {
+			continue
+		}
+		seen[lockedPkg.Version] = true
+		if v, err := semver.NewVersion(lockedPkg.Version); err == nil {
+			versions = append(versions, v)
+		}
+	}
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i].GreaterThan(versions[j]) })
+	result := make([]string, len(versions))
+	for i, v := range versions {
+		result[i] = v.Original()
+	}
+	return result
+}
+
+// LockfileNames are the lockfiles we know how to read, in order of precedence
+func LockfileNames() []string {
+	return []string{"npm-shrinkwrap.json", "package-lock.json"}
+}
+
+// UnmarshalLockfile parses lockfile versions 1, 2, and 3. Version 1 lockfiles
+// nest dependencies inside each other whereas later versions have a flat
+// 'packages' object keyed by path. We convert both into the flat form.
+func UnmarshalLockfile(r io.Reader, previousLockfile *Lockfile) (*Lockfile, error) {
+	var buf bytes.Buffer
+	h := sha256.New()
+	wr := io.MultiWriter(&buf, h)
+
+	if _, err := io.Copy(wr, r); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+
+	sha := h.Sum(nil)
+	if previousLockfile != nil && bytes.Equal(sha, previousLockfile.Sha) {
+		return previousLockfile, nil
+	}
+
+	lockfile := &Lockfile{Sha: sha, Packages: map[string]*LockedPackage{}}
+
+	lockfileVersion, err := jsonparser.GetInt(data, "lockfileVersion")
+	if err == nil {
+		lockfile.LockfileVersion = lockfileVersion
+	}
+
+	packages, dataType, _, err := jsonparser.Get(data, "packages")
+	if err == nil && dataType == jsonparser.Object {
+		err = jsonparser.ObjectEach(packages, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+			if dataType == jsonparser.Object {
+				lockfile.Packages[unescape(key)] = unmarshalLockedPackage(value)
+			}
+			return nil
+		})
+		return lockfile, err
+	}
+
+	dependencies, dataType, _, err := jsonparser.Get(data, "dependencies")
+	if err == nil && dataType == jsonparser.Object {
+		if err := unmarshalNestedLockedDependencies(dependencies, "", lockfile.Packages); err != nil {
+			return nil, err
+		}
+	}
+
+	return lockfile, nil
+}
+
+func unmarshalNestedLockedDependencies(data []byte, dir string, packages map[string]*LockedPackage) error {
+	return jsonparser.ObjectEach(data, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		if dataType != jsonparser.Object {
+			return nil
+		}
+		path := filepath.ToSlash(filepath.Join(dir, "node_modules", unescape(key)))
+		packages[path] = unmarshalLockedPackage(value)
+
+		nested, nestedDataType, _, err := jsonparser.Get(value, "dependencies")
+		if err == nil && nestedDataType == jsonparser.Object {
+			return unmarshalNestedLockedDependencies(nested, path, packages)
+		}
+		return nil
+	})
+}
+
+func unmarshalLockedPackage(data []byte) *LockedPackage {
+	lockedPkg := &LockedPackage{}
+
+	for _, mapping := range []struct {
+		field string
+		ptr   *string
+	}{
+		{field: "version", ptr: &lockedPkg.Version},
+		{field: "resolved", ptr: &lockedPkg.Resolved},
+		{field: "integrity", ptr: &lockedPkg.Integrity},
+		{field: "deprecated", ptr: &lockedPkg.Deprecated},
+	} {
+		value, err := jsonparser.GetString(data, mapping.field)
+		if err == nil {
+			*mapping.ptr = value
+		}
+	}
+
+	for _, mapping := range []struct {
+		field string
+		ptr   *bool
+	}{
+		{field: "dev", ptr: &lockedPkg.Dev},
+		{field: "optional", ptr: &lockedPkg.Optional},
+		{field: "link", ptr: &lockedPkg.Link},
+	} {
+		value, err := jsonparser.GetBoolean(data, mapping.field)
+		if err == nil {
+			*mapping.ptr = value
+		}
+	}
+
+	return lockedPkg
+}
+
+// GetLockfile returns nil if the package has no lockfile. Workspace members
+// share their root's lockfile.
+func (m *NpmManager) GetLockfile(pkg *Package) (*Lockfile, error) {
+	// we're called from background checks as well as refreshes
+	m.lockfileMutex.Lock()
+	defer m.lockfileMutex.Unlock()
+
+	dir := pkg.Path
+	if pkg.IsWorkspaceMember() {
+		dir = pkg.WorkspaceRootPath
+	}
+
+	for _, name := range LockfileNames() {
+		path := filepath.Join(dir, name)
+		if !FileExists(path) {
+			continue
+		}
+
+		file, err := os.OpenFile(path, os.O_RDONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		lockfile, err := UnmarshalLockfile(file, m.lockfiles[path])
+		if err != nil {
+			return nil, err
+		}
+		lockfile.Path = path
+		m.lockfiles[path] = lockfile
+		return lockfile, nil
+	}
+
+	return nil, nil
+}
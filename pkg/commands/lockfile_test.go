@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalLockfile(t *testing.T) {
+	type lookup struct {
+		dir      string
+		name     string
+		expected *LockedPackage
+	}
+
+	type scenario struct {
+		filename                string
+		expectedLockfileVersion int64
+		lookups                 []lookup
+	}
+
+	scenarios := []scenario{
+		{
+			"testfiles/lockfiles/v1.json",
+			1,
+			[]lookup{
+				{"", "a", &LockedPackage{Version: "1.2.3", Resolved: "https://registry.npmjs.org/a/-/a-1.2.3.tgz", Integrity: "sha512-aaa"}},
+				{"", "b", &LockedPackage{Version: "1.0.0", Resolved: "https://registry.npmjs.org/b/-/b-1.0.0.tgz", Integrity: "sha512-bbb1", Dev: true}},
+				{"node_modules/a", "b", &LockedPackage{Version: "2.0.1", Resolved: "https://registry.npmjs.org/b/-/b-2.0.1.tgz", Integrity: "sha512-bbb2"}},
+				{"", "c", nil},
+			},
+		},
+		{
+			"testfiles/lockfiles/v2.json",
+			2,
+			[]lookup{
+				{"", "a", &LockedPackage{Version: "1.2.3", Resolved: "https://registry.npmjs.org/a/-/a-1.2.3.tgz", Integrity: "sha512-aaa"}},
+				{"node_modules/a", "b", &LockedPackage{Version: "2.0.1", Resolved: "https://registry.npmjs.org/b/-/b-2.0.1.tgz", Integrity: "sha512-bbb2"}},
+				{"", "member", &LockedPackage{Resolved: "packages/member", Link: true}},
+				// workspace members fall back to the hoisted packages at the root
+				{"packages/member", "a", &LockedPackage{Version: "1.2.3", Resolved: "https://registry.npmjs.org/a/-/a-1.2.3.tgz", Integrity: "sha512-aaa"}},
+				{"packages/member", "c", &LockedPackage{Version: "3.0.0", Optional: true, Deprecated: "use d instead"}},
+			},
+		},
+		{
+			"testfiles/lockfiles/v3.json",
+			3,
+			[]lookup{
+				{"", "@scope/a", &LockedPackage{Version: "0.1.0", Integrity: "sha512-scoped"}},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.filename, func(t *testing.T) {
+			file, err := os.Open(s.filename)
+			assert.NoError(t, err)
+			defer file.Close()
+
+			lockfile, err := UnmarshalLockfile(file, nil)
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectedLockfileVersion, lockfile.LockfileVersion)
+
+			for _, l := range s.lookups {
+				assert.EqualValues(t, l.expected, lockfile.Lookup(l.dir, l.name))
+			}
+		})
+	}
+}
+
+func TestLockfileVersions(t *testing.T) {
+	file, err := os.Open("testfiles/lockfiles/v1.json")
+	assert.NoError(t, err)
+	defer file.Close()
+
+	lockfile, err := UnmarshalLockfile(file, nil)
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, []string{"2.0.1", "1.0.0"}, lockfile.Versions("b"))
+	assert.EqualValues(t, []string{"1.2.3"}, lockfile.Versions("a"))
+	assert.EqualValues(t, []string{}, lockfile.Versions("c"))
+}
+
+func TestDependencyHasLockDrift(t *testing.T) {
+	type scenario struct {
+		name     string
+		dep      *Dependency
+		expected bool
+	}
+
+	scenarios := []scenario{
+		{
+			"matches lockfile",
+			&Dependency{Present: true, PackageConfig: &PackageConfig{Version: "1.0.0"}, Locked: &LockedPackage{Version: "1.0.0"}},
+			false,
+		},
+		{
+			"differs from lockfile",
+			&Dependency{Present: true, PackageConfig: &PackageConfig{Version: "1.0.1"}, Locked: &LockedPackage{Version: "1.0.0"}},
+			true,
+		},
+		{
+			"installed but not in lockfile",
+			&Dependency{Present: true, PackageConfig: &PackageConfig{Version: "1.0.1"}},
+			true,
+		},
+		{
+			"not installed",
+			&Dependency{Locked: &LockedPackage{Version: "1.0.0"}},
+			false,
+		},
+		{
+			"linked",
+			&Dependency{Present: true, LinkPath: "/a", PackageConfig: &PackageConfig{Version: "2.0.0"}, Locked: &LockedPackage{Version: "1.0.0"}},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, s.dep.hasLockDrift())
+		})
+	}
+}
@@ -1,13 +1,11 @@
 package commands
 
 import (
-	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
-	"github.com/buger/jsonparser"
 	"github.com/jesseduffield/lazynpm/pkg/config"
 	"github.com/jesseduffield/lazynpm/pkg/i18n"
 	"github.com/sirupsen/logrus"
@@ -20,6 +18,27 @@ type NpmManager struct {
 	Tr        *i18n.Localizer
 	Config    config.AppConfigurer
 	NpmRoot   string
+	// cache of where each package manager puts its globally linked packages
+	globalLinkDirs map[string]string
+	// cache of parsed lockfiles, keyed by path
+	lockfiles     map[string]*Lockfile
+	lockfileMutex sync.Mutex
+	// cache of parsed package.json files in node_modules, keyed by the package's directory
+	packageConfigs     map[string]*PackageConfig
+	packageConfigMutex sync.Mutex
+	// cache of tarball contents, keyed by path
+	tarballContents      map[string]*TarballContents
+	tarballContentsMutex sync.Mutex
+	// cache of tarballs we've already hashed, keyed by path
+	tarballs      map[string]*tarballHashes
+	tarballsMutex sync.Mutex
+	// the versions of node and npm we're running, once we've asked
+	engineVersions      *EngineVersions
+	engineVersionsMutex sync.Mutex
+	// cache of what the registry says about deprecations, keyed by
+	// name@version. Empty if the version isn't deprecated
+	registryDeprecations     map[string]string
+	registryDeprecationMutex sync.Mutex
 }
 
 // NewNpmManager it runs git commands
@@ -36,11 +55,51 @@ func NewNpmManager(log *logrus.Entry, osCommand *OSCommand, tr *i18n.Localizer,
 		Tr:        tr,
 		Config:    config,
 		NpmRoot:   npmRoot,
+		globalLinkDirs: map[string]string{
+			"npm": npmRoot,
+		},
+		lockfiles:       map[string]*Lockfile{},
+		packageConfigs:  map[string]*PackageConfig{},
+		tarballContents: map[string]*TarballContents{},
+		tarballs:        map[string]*tarballHashes{},
+
+		registryDeprecations: map[string]string{},
 	}, nil
 }
 
-func (m *NpmManager) IsLinked(name string, path string) (bool, error) {
-	globalPath := filepath.Join(m.NpmRoot, name)
+// getGlobalLinkDir returns an empty string if the package manager isn't
+// installed or can't tell us where it links things to
+func (m *NpmManager) getGlobalLinkDir(pm PackageManager) string {
+	if dir, ok := m.globalLinkDirs[pm.Name()]; ok {
+		return dir
+	}
+
+	dir, err := pm.GlobalLinkDir(m.OSCommand)
+	if err != nil {
+		m.Log.Error(err)
+		dir = ""
+	}
+	m.globalLinkDirs[pm.Name()] = dir
+	return dir
+}
+
+// PackageManagerFor returns the package manager that a package should use,
+// honouring any override the user has set for that package
+func (m *NpmManager) PackageManagerFor(path string, pkgConfig *PackageConfig) PackageManager {
+	if name, ok := m.Config.GetAppState().PackageManagers[path]; ok {
+		if pm := PackageManagerByName(name); pm != nil {
+			return pm
+		}
+	}
+
+	return DetectPackageManager(path, pkgConfig)
+}
+
+func (m *NpmManager) IsLinked(linkDir string, name string, path string) (bool, error) {
+	if linkDir == "" {
+		return false, nil
+	}
+	globalPath := filepath.Join(linkDir, name)
 	fileInfo, err := os.Lstat(globalPath)
 	if err != nil {
 		if err == os.ErrNotExist {
@@ -71,34 +130,107 @@ func (m *NpmManager) GetPackages(paths []string, previousPackages []*Package) ([
 	}
 
 	pkgs := make([]*Package, 0, len(paths))
+	pkgMap := map[string]*Package{}
 
 	for _, path := range paths {
-		packageConfigPath := filepath.Join(path, "package.json")
-		if !FileExists(packageConfigPath) {
+		if pkgMap[filepath.Clean(path)] != nil {
+			// already added as a workspace member
 			continue
 		}
 
-		file, err := os.OpenFile(packageConfigPath, os.O_RDONLY, 0644)
+		pkg, err := m.getPackage(path, nil, previousPackageConfigMap[path])
 		if err != nil {
-			m.Log.Error(err)
+			return nil, err
+		}
+		if pkg == nil {
 			continue
 		}
-		pkgConfig, err := UnmarshalPackageConfig(file, previousPackageConfigMap[path])
-		if err != nil {
-			return nil, err
+		pkgs = append(pkgs, pkg)
+		pkgMap[filepath.Clean(path)] = pkg
+
+		// workspace members go straight after their root package
+		for _, memberPath := range ExpandWorkspaces(path, pkg.Config.Workspaces) {
+			member, err := m.getPackage(memberPath, pkg, previousPackageConfigMap[memberPath])
+			if err != nil {
+				return nil, err
+			}
+			if member == nil {
+				continue
+			}
+
+			// a member that came before its root in the list of paths gets
+			// moved under its root, unless it's the current package, which
+			// always comes first
+			if existingPkg := pkgMap[memberPath]; existingPkg != nil {
+				if existingPkg == pkgs[0] {
+					pkgs[0] = member
+					pkgMap[memberPath] = member
+					continue
+				}
+				pkgs = removePackage(pkgs, existingPkg)
+			}
+			pkgs = append(pkgs, member)
+			pkgMap[memberPath] = member
 		}
-		linked, err := m.IsLinked(pkgConfig.Name, path)
-		if err != nil {
-			return nil, err
+	}
+	return pkgs, nil
+}
+
+func removePackage(pkgs []*Package, pkgToRemove *Package) []*Package {
+	result := make([]*Package, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		if pkg != pkgToRemove {
+			result = append(result, pkg)
 		}
+	}
+	return result
+}
 
-		pkgs = append(pkgs, &Package{
-			Config:         *pkgConfig,
-			Path:           path,
-			LinkedGlobally: linked,
-		})
+// getPackage returns nil if there is no package.json at the given path. If
+// the package is a workspace member, workspaceRoot is the root package.
+func (m *NpmManager) getPackage(path string, workspaceRoot *Package, previousPackageConfig *PackageConfig) (*Package, error) {
+	packageConfigPath := filepath.Join(path, "package.json")
+	if !FileExists(packageConfigPath) {
+		return nil, nil
 	}
-	return pkgs, nil
+
+	file, err := os.OpenFile(packageConfigPath, os.O_RDONLY, 0644)
+	if err != nil {
+		m.Log.Error(err)
+		return nil, nil
+	}
+	defer file.Close()
+
+	pkgConfig, err := UnmarshalPackageConfig(file, previousPackageConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// workspace members share their root's package manager unless told otherwise
+	var pm PackageManager
+	workspaceRootPath := ""
+	if workspaceRoot != nil {
+		workspaceRootPath = workspaceRoot.Path
+		pm = workspaceRoot.PackageManager
+		if _, ok := m.Config.GetAppState().PackageManagers[path]; ok || pkgConfig.PackageManager != "" {
+			pm = m.PackageManagerFor(path, pkgConfig)
+		}
+	} else {
+		pm = m.PackageManagerFor(path, pkgConfig)
+	}
+
+	linked, err := m.IsLinked(m.getGlobalLinkDir(pm), pkgConfig.Name, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Package{
+		Config:            *pkgConfig,
+		Path:              path,
+		LinkedGlobally:    linked,
+		PackageManager:    pm,
+		WorkspaceRootPath: workspaceRootPath,
+	}, nil
 }
 
 func (m *NpmManager) ChdirToPackageRoot() (bool, error) {
@@ -129,11 +261,30 @@ func (m *NpmManager) ChdirToPackageRoot() (bool, error) {
 func (m *NpmManager) GetDeps(currentPkg *Package, previousDeps []*Dependency) ([]*Dependency, error) {
 	deps := currentPkg.SortedDependencies(previousDeps)
 
+	lockfile, err := m.GetLockfile(currentPkg)
+	if err != nil {
+		// swallowing error: a broken lockfile shouldn't stop us showing deps
+		m.Log.Error(err)
+	}
+	lockfileDir := ""
+	if lockfile != nil {
+		lockfileDir, err = filepath.Rel(filepath.Dir(lockfile.Path), currentPkg.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for _, dep := range deps {
 		depPath := filepath.Join(currentPkg.Path, "node_modules", dep.Name)
 		dep.Path = depPath
 		dep.LinkPath = ""
 		dep.ParentPackagePath = currentPkg.Path
+		dep.Present = false
+		dep.Locked = nil
+		dep.LockDrift = false
+		if lockfile != nil {
+			dep.Locked = lockfile.Lookup(lockfileDir, dep.Name)
+		}
 		fileInfo, err := os.Lstat(depPath)
 		if err != nil {
 			// must not be present in node modules
@@ -142,14 +293,7 @@ func (m *NpmManager) GetDeps(currentPkg *Package, previousDeps []*Dependency) ([
 		dep.Present = true
 
 		// get the actual version of the package in node modules
-		packageConfigPath := filepath.Join(depPath, "package.json")
-		file, err := os.OpenFile(packageConfigPath, os.O_RDONLY, 0644)
-		if err != nil {
-			m.Log.Error(err)
-			continue
-		}
-
-		pkgConfig, err := UnmarshalPackageConfig(file, dep.PackageConfig)
+		pkgConfig, err := m.getPackageConfig(depPath)
 		if err != nil {
 			dep.PackageConfig = nil
 			// swallowing error
@@ -171,70 +315,58 @@ func (m *NpmManager) GetDeps(currentPkg *Package, previousDeps []*Dependency) ([
 		dep.LinkPath = linkPath
 	}
 
-	return deps, nil
-}
-
-func (m *NpmManager) GetTarballs(currentPkg *Package) ([]*Tarball, error) {
-	// would be nice if I had a guarantee on the directory I was checking but this should do
-	paths, err := filepath.Glob("*.tgz")
-	if err != nil {
-		return nil, err
-	}
-
-	tarballs := make([]*Tarball, len(paths))
-	for i, path := range paths {
-		tarballs[i] = &Tarball{
-			Path: path,
-			Name: filepath.Base(path),
+	if lockfile != nil {
+		for _, dep := range deps {
+			dep.LockDrift = dep.hasLockDrift()
 		}
 	}
 
-	return tarballs, nil
+	return deps, nil
 }
 
-func (m *NpmManager) RemoveScript(scriptName string, packageJsonPath string) error {
-	config, err := ioutil.ReadFile(packageJsonPath)
+// getPackageConfig reads the package.json in the given directory, reusing what
+// we parsed last time if the file hasn't changed
+func (m *NpmManager) getPackageConfig(dir string) (*PackageConfig, error) {
+	file, err := os.OpenFile(filepath.Join(dir, "package.json"), os.O_RDONLY, 0644)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer file.Close()
 
-	updatedConfig := jsonparser.Delete(config, "scripts", scriptName)
-
-	return ioutil.WriteFile(packageJsonPath, updatedConfig, 0644)
-}
+	m.packageConfigMutex.Lock()
+	defer m.packageConfigMutex.Unlock()
 
-func (m *NpmManager) EditDepConstraint(dep *Dependency, packageJsonPath string, constraint string) error {
-	config, err := ioutil.ReadFile(packageJsonPath)
+	pkgConfig, err := UnmarshalPackageConfig(file, m.packageConfigs[dir])
 	if err != nil {
-		return err
-	}
-
-	updatedConfig, err := jsonparser.Set(config, jsonStringValue(constraint), dep.KindKey(), dep.Name)
-	if err != nil {
-		return err
+		return nil, err
 	}
+	m.packageConfigs[dir] = pkgConfig
+	return pkgConfig, nil
+}
 
-	return ioutil.WriteFile(packageJsonPath, updatedConfig, 0644)
+func (m *NpmManager) RemoveScript(scriptName string, packageJsonPath string) error {
+	return m.EditPackageJSON(packageJsonPath, func(packageJSON *PackageJSON) error {
+		return packageJSON.Delete("scripts", scriptName)
+	})
 }
 
-func jsonStringValue(str string) []byte {
-	return []byte(fmt.Sprintf("\"%s\"", strings.Replace(str, "\"", "\\\"", -1)))
+func (m *NpmManager) EditDepConstraint(dep *Dependency, packageJsonPath string, constraint string) error {
+	return m.EditPackageJSON(packageJsonPath, func(packageJSON *PackageJSON) error {
+		return packageJSON.SetDependency(dep.KindKey(), dep.Name, constraint)
+	})
 }
 
 func (m *NpmManager) EditOrAddScript(scriptName string, packageJsonPath string, newName string, newCommand string) error {
-	// TODO: ensure there is a 'scripts' key
-
-	config, err := ioutil.ReadFile(packageJsonPath)
-	if err != nil {
-		return err
-	}
-
-	updatedConfig := jsonparser.Delete(config, "scripts", scriptName)
-
-	updatedConfig, err = jsonparser.Set(updatedConfig, jsonStringValue(newCommand), "scripts", newName)
-	if err != nil {
-		return err
-	}
-
-	return ioutil.WriteFile(packageJsonPath, updatedConfig, 0644)
+	return m.EditPackageJSON(packageJsonPath, func(packageJSON *PackageJSON) error {
+		if newName != scriptName && packageJSON.Has("scripts", scriptName) {
+			// renaming onto an existing script replaces it
+			if err := packageJSON.Delete("scripts", newName); err != nil {
+				return err
+			}
+			if err := packageJSON.RenameKey(newName, "scripts", scriptName); err != nil {
+				return err
+			}
+		}
+		return packageJSON.SetString(newCommand, "scripts", newName)
+	})
 }
@@ -0,0 +1,308 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// NpmrcLayer is one source of npm config: the environment or an .npmrc file
+type NpmrcLayer struct {
+	// Name is one of 'environment', 'project', 'user', 'global', or 'builtin'
+	Name string
+	// Path is empty for the environment layer
+	Path string
+	// Exists is false if there is no file at Path
+	Exists bool
+	Values map[string]string
+}
+
+// NpmConfig is npm's config as layers, highest precedence first
+type NpmConfig struct {
+	Layers []*NpmrcLayer
+}
+
+// NpmConfigEntry is the effective value of a key along with where it came from
+type NpmConfigEntry struct {
+	Key    string
+	Value  string
+	Source *NpmrcLayer
+	// Overridden are the lower precedence layers that also set the key
+	Overridden []*NpmrcLayer
+}
+
+// Get returns the effective value of the key, and the layer it came from
+// (nil if no layer sets it)
+func (c *NpmConfig) Get(key string) (string, *NpmrcLayer) {
+	for _, layer := range c.Layers {
+		if value, ok := layer.Values[key]; ok {
+			return value, layer
+		}
+	}
+	return "", nil
+}
+
+// Entries returns the effective value of every key that's been set, sorted by key
+func (c *NpmConfig) Entries() []*NpmConfigEntry {
+	entryMap := map[string]*NpmConfigEntry{}
+	entries := []*NpmConfigEntry{}
+	for _, layer := range c.Layers {
+		for key, value := range layer.Values {
+			if entry, ok := entryMap[key]; ok {
+				entry.Overridden = append(entry.Overridden, layer)
+				continue
+			}
+			entry := &NpmConfigEntry{Key: key, Value: value, Source: layer}
+			entryMap[key] = entry
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+// RegistryFor returns the registry the named package comes from, going by any
+// registry configured for its scope, then the default registry
+func (c *NpmConfig) RegistryFor(name string) string {
+	if strings.HasPrefix(name, "@") && strings.Contains(name, "/") {
+		scope := name[:strings.Index(name, "/")]
+		if registry, _ := c.Get(scope + ":registry"); registry != "" {
+			return withTrailingSlash(registry)
+		}
+	}
+	if registry, _ := c.Get("registry"); registry != "" {
+		return withTrailingSlash(registry)
+	}
+	return DefaultRegistryURL
+}
+
+// AuthorizationFor returns the Authorization header npm would send to the
+// given registry, or an empty string if the config has no credentials for it.
+// Credentials are keyed by the registry's URL minus its protocol, e.g.
+// '//npm.acme.dev/:_authToken', and npm also uses those set against any
+// parent path on the same host
+func (c *NpmConfig) AuthorizationFor(registryURL string) string {
+	parsed, err := url.Parse(registryURL)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+
+	prefix := "//" + parsed.Host + withTrailingSlash(parsed.Path)
+	for {
+		if token, _ := c.Get(prefix + ":_authToken"); token != "" {
+			return "Bearer " + token
+		}
+		if auth, _ := c.Get(prefix + ":_auth"); auth != "" {
+			return "Basic " + auth
+		}
+		username, _ := c.Get(prefix + ":username")
+		password, _ := c.Get(prefix + ":_password")
+		if username != "" && password != "" {
+			// the password is stored base64 encoded
+			decoded, err := base64.StdEncoding.DecodeString(password)
+			if err == nil {
+				return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+string(decoded)))
+			}
+		}
+
+		if strings.Count(prefix, "/") <= 3 {
+			return ""
+		}
+		// e.g. '//host/a/b/' becomes '//host/a/'
+		prefix = prefix[:strings.LastIndex(strings.TrimSuffix(prefix, "/"), "/")+1]
+	}
+}
+
+func withTrailingSlash(url string) string {
+	if strings.HasSuffix(url, "/") {
+		return url
+	}
+	return url + "/"
+}
+
+var secretNpmConfigKeyRegex = regexp.MustCompile(`(?i)(_authtoken|_auth|_password|password|token|secret|certfile|keyfile)$`)
+
+// IsSecretNpmConfigKey tells us whether a key's value shouldn't be shown on screen
+func IsSecretNpmConfigKey(key string) bool {
+	return secretNpmConfigKeyRegex.MatchString(key)
+}
+
+// MaskNpmConfigValue hides all but the last few characters of a secret
+func MaskNpmConfigValue(key string, value string) string {
+	if !IsSecretNpmConfigKey(key) {
+		return value
+	}
+	if len(value) <= 8 {
+		return strings.Repeat("*", len(value))
+	}
+	return strings.Repeat("*", 8) + value[len(value)-4:]
+}
+
+var envVarRegex = regexp.MustCompile(`\$\{([^${}?]+)(\?)?\}`)
+
+// interpolateEnv replaces '${VAR}' with the value of the environment variable.
+// As with npm, '${VAR?}' becomes blank if the variable isn't set, whereas we
+// leave '${VAR}' as-is so that it's clear something is missing
+func interpolateEnv(str string, getenv func(string) string) string {
+	return envVarRegex.ReplaceAllStringFunc(str, func(match string) string {
+		groups := envVarRegex.FindStringSubmatch(match)
+		if value := getenv(groups[1]); value != "" {
+			return value
+		}
+		if groups[2] == "?" {
+			return ""
+		}
+		return match
+	})
+}
+
+// ParseNpmrc parses an ini-style .npmrc file. Keys ending in '[]' build up a
+// list, which we join with commas
+func ParseNpmrc(r io.Reader, getenv func(string) string) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		idx := strings.Index(line, "=")
+		key, value := line, "true"
+		if idx != -1 {
+			key, value = strings.TrimSpace(line[:idx]), parseNpmrcValue(line[idx+1:])
+		}
+		key = interpolateEnv(key, getenv)
+		value = interpolateEnv(value, getenv)
+
+		if strings.HasSuffix(key, "[]") {
+			key = strings.TrimSuffix(key, "[]")
+			if existing, ok := values[key]; ok {
+				value = existing + "," + value
+			}
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+func parseNpmrcValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	// inline comments need whitespace before them, so that urls with a '#' survive
+	for _, marker := range []string{" ;", " #", "\t;", "\t#"} {
+		if idx := strings.Index(value, marker); idx != -1 {
+			value = strings.TrimSpace(value[:idx])
+		}
+	}
+	return value
+}
+
+// environmentNpmrcLayer picks out npm_config_* environment variables, which
+// take precedence over every file
+func environmentNpmrcLayer(environ []string) *NpmrcLayer {
+	layer := &NpmrcLayer{Name: "environment", Exists: true, Values: map[string]string{}}
+	for _, envVar := range environ {
+		idx := strings.Index(envVar, "=")
+		if idx == -1 {
+			continue
+		}
+		name, value := envVar[:idx], envVar[idx+1:]
+		if !strings.HasPrefix(strings.ToLower(name), "npm_config_") || value == "" {
+			continue
+		}
+		key := strings.ToLower(strings.TrimPrefix(strings.ToLower(name), "npm_config_"))
+		// these only tell npm where to find the files, they aren't config themselves
+		if key == "userconfig" || key == "globalconfig" {
+			continue
+		}
+		// like npm, we keep a leading underscore so that e.g.
+		// npm_config__authToken still sets _authtoken
+		layer.Values[key[:1]+strings.Replace(key[1:], "_", "-", -1)] = value
+	}
+	return layer
+}
+
+// npmrcFile is an .npmrc file we want to read, which may not exist
+type npmrcFile struct {
+	name string
+	path string
+}
+
+// npmrcFiles lists npm's config files in order of precedence. npmRoot is the
+// global node_modules folder, which npm's own folder and prefix are relative to
+func npmrcFiles(pkg *Package, npmRoot string, getenv func(string) string) []npmrcFile {
+	files := []npmrcFile{}
+
+	// workspace members share the root's config
+	projectDir := pkg.Path
+	if pkg.IsWorkspaceMember() {
+		projectDir = pkg.WorkspaceRootPath
+	}
+	files = append(files, npmrcFile{name: "project", path: filepath.Join(projectDir, ".npmrc")})
+
+	userConfig := getenv("NPM_CONFIG_USERCONFIG")
+	if userConfig == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			userConfig = filepath.Join(home, ".npmrc")
+		}
+	}
+	if userConfig != "" {
+		files = append(files, npmrcFile{name: "user", path: userConfig})
+	}
+
+	globalConfig := getenv("NPM_CONFIG_GLOBALCONFIG")
+	if globalConfig == "" && npmRoot != "" {
+		// npm root -g is {prefix}/lib/node_modules, except on windows where it's {prefix}/node_modules
+		prefix := filepath.Dir(filepath.Dir(npmRoot))
+		if runtime.GOOS == "windows" {
+			prefix = filepath.Dir(npmRoot)
+		}
+		globalConfig = filepath.Join(prefix, "etc", "npmrc")
+	}
+	if globalConfig != "" {
+		files = append(files, npmrcFile{name: "global", path: globalConfig})
+	}
+
+	if npmRoot != "" {
+		files = append(files, npmrcFile{name: "builtin", path: filepath.Join(npmRoot, "npm", "npmrc")})
+	}
+
+	return files
+}
+
+// loadNpmConfig reads the given files, skipping those that don't exist
+func loadNpmConfig(files []npmrcFile, environ []string, getenv func(string) string) *NpmConfig {
+	config := &NpmConfig{Layers: []*NpmrcLayer{environmentNpmrcLayer(environ)}}
+	for _, file := range files {
+		layer := &NpmrcLayer{Name: file.name, Path: file.path, Values: map[string]string{}}
+		config.Layers = append(config.Layers, layer)
+
+		f, err := os.Open(file.path)
+		if err != nil {
+			continue
+		}
+		values, err := ParseNpmrc(f, getenv)
+		f.Close()
+		if err != nil {
+			continue
+		}
+		layer.Exists = true
+		layer.Values = values
+	}
+	return config
+}
+
+// GetNpmConfig reads npm's config as it applies to the given package
+func (m *NpmManager) GetNpmConfig(pkg *Package) *NpmConfig {
+	return loadNpmConfig(npmrcFiles(pkg, m.NpmRoot, os.Getenv), os.Environ(), os.Getenv)
+}
@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNpmrc(t *testing.T) {
+	env := map[string]string{"NPM_TOKEN": "abc123", "REGISTRY_HOST": "npm.example.com"}
+	getenv := func(name string) string { return env[name] }
+
+	npmrc := `
+; a comment
+# another comment
+registry = https://${REGISTRY_HOST}/
+@acme:registry=https://npm.acme.dev/  ; inline comment
+//npm.acme.dev/:_authToken=${NPM_TOKEN}
+//other.dev/:_authToken=${MISSING_TOKEN}
+optional=${MISSING_TOKEN?}
+message="quoted ; not a comment"
+url=https://example.com/#fragment
+save-exact
+ca[]=first
+ca[]=second
+[section]
+`
+
+	values, err := ParseNpmrc(strings.NewReader(npmrc), getenv)
+	assert.NoError(t, err)
+	assert.EqualValues(t, map[string]string{
+		"registry":                   "https://npm.example.com/",
+		"@acme:registry":             "https://npm.acme.dev/",
+		"//npm.acme.dev/:_authToken": "abc123",
+		"//other.dev/:_authToken":    "${MISSING_TOKEN}",
+		"optional":                   "",
+		"message":                    "quoted ; not a comment",
+		"url":                        "https://example.com/#fragment",
+		"save-exact":                 "true",
+		"ca":                         "first,second",
+	}, values)
+}
+
+func TestLoadNpmConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := []npmrcFile{}
+	for _, file := range []struct {
+		name    string
+		content string
+	}{
+		{name: "project", content: "registry=https://project.dev/\n@acme:registry=https://npm.acme.dev\n"},
+		{name: "user", content: "registry=https://user.dev/\n//npm.acme.dev/:_authToken=supersecrettoken\n"},
+		{name: "global", content: "save-exact=true\n"},
+	} {
+		path := filepath.Join(dir, file.name)
+		assert.NoError(t, ioutil.WriteFile(path, []byte(file.content), 0644))
+		files = append(files, npmrcFile{name: file.name, path: path})
+	}
+	files = append(files, npmrcFile{name: "builtin", path: filepath.Join(dir, "missing")})
+
+	config := loadNpmConfig(files, []string{"npm_config_save_exact=false", "NPM_CONFIG_USERCONFIG=/elsewhere", "PATH=/bin"}, os.Getenv)
+
+	assert.EqualValues(t, []string{"environment", "project", "user", "global", "builtin"}, layerNames(config.Layers))
+	assert.False(t, config.Layers[4].Exists)
+
+	value, layer := config.Get("save-exact")
+	assert.EqualValues(t, "false", value)
+	assert.EqualValues(t, "environment", layer.Name)
+
+	_, layer = config.Get("userconfig")
+	assert.Nil(t, layer)
+
+	entries := config.Entries()
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+	assert.EqualValues(t, []string{"//npm.acme.dev/:_authToken", "@acme:registry", "registry", "save-exact"}, keys)
+	assert.EqualValues(t, "project", entries[2].Source.Name)
+	assert.EqualValues(t, []string{"user"}, layerNames(entries[2].Overridden))
+
+	assert.EqualValues(t, "https://npm.acme.dev/", config.RegistryFor("@acme/widgets"))
+	assert.EqualValues(t, "https://project.dev/", config.RegistryFor("@other/widgets"))
+	assert.EqualValues(t, "https://project.dev/", config.RegistryFor("lodash"))
+	assert.EqualValues(t, DefaultRegistryURL, (&NpmConfig{}).RegistryFor("lodash"))
+}
+
+func TestNpmConfigAuthorizationFor(t *testing.T) {
+	config := &NpmConfig{Layers: []*NpmrcLayer{
+		{Name: "user", Values: map[string]string{
+			"//npm.acme.dev/:_authToken":          "token",
+			"//npm.acme.dev/private/:_authToken":  "private-token",
+			"//basic.dev/:_auth":                  "dXNlcjpwYXNz",
+			"//legacy.dev/npm/:username":          "user",
+			"//legacy.dev/npm/:_password":         "cGFzcw==",
+			"//elsewhere.dev/:_authToken":         "unrelated",
+			"//npm.acme.dev.evil.com/:_authToken": "stolen",
+		}},
+	}}
+
+	type scenario struct {
+		registryURL string
+		expected    string
+	}
+
+	scenarios := []scenario{
+		{registryURL: "https://npm.acme.dev/", expected: "Bearer token"},
+		{registryURL: "https://npm.acme.dev/private/", expected: "Bearer private-token"},
+		{registryURL: "https://npm.acme.dev/other/nested/", expected: "Bearer token"},
+		{registryURL: "https://basic.dev", expected: "Basic dXNlcjpwYXNz"},
+		{registryURL: "https://legacy.dev/npm/", expected: "Basic dXNlcjpwYXNz"},
+		{registryURL: "https://legacy.dev/", expected: ""},
+		{registryURL: DefaultRegistryURL, expected: ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.registryURL, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, config.AuthorizationFor(s.registryURL))
+		})
+	}
+}
+
+func TestEnvironmentNpmrcLayer(t *testing.T) {
+	layer := environmentNpmrcLayer([]string{
+		"npm_config_save_exact=true",
+		"npm_config__authToken=supersecrettoken",
+		"NPM_CONFIG_USERCONFIG=/elsewhere",
+		"npm_config_empty=",
+		"PATH=/bin",
+	})
+
+	assert.EqualValues(t, map[string]string{
+		"save-exact": "true",
+		"_authtoken": "supersecrettoken",
+	}, layer.Values)
+}
+
+func TestMaskNpmConfigValue(t *testing.T) {
+	type scenario struct {
+		key      string
+		value    string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{key: "registry", value: "https://registry.npmjs.org/", expected: "https://registry.npmjs.org/"},
+		{key: "//npm.acme.dev/:_authToken", value: "supersecrettoken", expected: "********oken"},
+		{key: "//npm.acme.dev/:_auth", value: "dXNlcjpwYXNz", expected: "********YXNz"},
+		{key: "_password", value: "short", expected: "*****"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.key, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, MaskNpmConfigValue(s.key, s.value))
+		})
+	}
+}
+
+func layerNames(layers []*NpmrcLayer) []string {
+	names := make([]string, len(layers))
+	for i, layer := range layers {
+		names[i] = layer.Name
+	}
+	return names
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/go-errors/errors"
+)
+
+// OutdatedInfo is what `npm outdated --json` tells us about a dependency
+type OutdatedInfo struct {
+	Current string
+	// Wanted is the highest version satisfying the dependency's constraint
+	Wanted string
+	Latest string
+}
+
+// ParseOutdated parses the output of `npm outdated --json` (or
+// `pnpm outdated --format json`), returning a map of dependency names to their
+// outdated info. When run against a workspace, npm gives an array of entries per
+// dependency, in which case we take the one whose dependent is the given package.
+func ParseOutdated(output string, pkgName string) (map[string]*OutdatedInfo, error) {
+	outdated := map[string]*OutdatedInfo{}
+
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return outdated, nil
+	}
+	// skip past any warnings that were printed before the json
+	data := []byte(output)
+	if idx := strings.Index(output, "{"); idx > 0 {
+		data = data[idx:]
+	}
+
+	// npm reports failures like this too, but we need to tell that apart from
+	// a dependency that's actually called 'error'
+	if errData, dataType, _, err := jsonparser.Get(data, "error"); err == nil && dataType == jsonparser.Object {
+		code, codeErr := jsonparser.GetString(errData, "code")
+		summary, summaryErr := jsonparser.GetString(errData, "summary")
+		if codeErr == nil || summaryErr == nil {
+			if summary == "" {
+				summary = code
+			}
+			return nil, errors.New(summary)
+		}
+	}
+
+	err := jsonparser.ObjectEach(data, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		switch dataType {
+		case jsonparser.Object:
+			outdated[unescape(key)] = unmarshalOutdatedInfo(value)
+		case jsonparser.Array:
+			_, _ = jsonparser.ArrayEach(value, func(entry []byte, dataType jsonparser.ValueType, offset int, err error) {
+				if dataType != jsonparser.Object {
+					return
+				}
+				dependent, _ := jsonparser.GetString(entry, "dependent")
+				if _, ok := outdated[unescape(key)]; !ok || dependent == pkgName {
+					outdated[unescape(key)] = unmarshalOutdatedInfo(entry)
+				}
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.New("could not parse outdated output: " + output)
+	}
+
+	return outdated, nil
+}
+
+func unmarshalOutdatedInfo(data []byte) *OutdatedInfo {
+	info := &OutdatedInfo{}
+	for _, mapping := range []struct {
+		field string
+		ptr   *string
+	}{
+		{field: "current", ptr: &info.Current},
+		{field: "wanted", ptr: &info.Wanted},
+		{field: "latest", ptr: &info.Latest},
+	} {
+		value, err := jsonparser.GetString(data, mapping.field)
+		if err == nil {
+			*mapping.ptr = value
+		}
+	}
+	return info
+}
+
+// GetOutdated returns nil if the package's package manager can't tell us what's outdated
+func (m *NpmManager) GetOutdated(pkg *Package, opts CmdOpts) (map[string]*OutdatedInfo, error) {
+	cmdStr := pkg.PackageManager.Outdated(opts)
+	if cmdStr == "" {
+		return nil, nil
+	}
+
+	// npm outdated exits with a non-zero code when anything is outdated, so we
+	// only care about the error if we can't parse the output
+	output, cmdErr := m.OSCommand.RunCommandWithOutput(cmdStr)
+	outdated, err := ParseOutdated(output, pkg.Config.Name)
+	if err != nil {
+		// npm's own error report is more useful than its exit code
+		if cmdErr != nil && !strings.Contains(output, "{") {
+			return nil, cmdErr
+		}
+		return nil, err
+	}
+	return outdated, nil
+}
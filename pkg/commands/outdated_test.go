@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOutdated(t *testing.T) {
+	type scenario struct {
+		name     string
+		output   string
+		expected map[string]*OutdatedInfo
+	}
+
+	scenarios := []scenario{
+		{
+			"nothing outdated",
+			"",
+			map[string]*OutdatedInfo{},
+		},
+		{
+			"single package",
+			`{
+  "chalk": {"current": "2.4.1", "wanted": "2.4.2", "latest": "5.3.0", "dependent": "app", "location": "node_modules/chalk"},
+  "@scope/missing": {"wanted": "1.0.0", "latest": "1.1.0", "dependent": "app"}
+}`,
+			map[string]*OutdatedInfo{
+				"chalk":          {Current: "2.4.1", Wanted: "2.4.2", Latest: "5.3.0"},
+				"@scope/missing": {Wanted: "1.0.0", Latest: "1.1.0"},
+			},
+		},
+		{
+			"workspaces give arrays and warnings can come first",
+			`npm WARN config something
+{
+  "chalk": [
+    {"current": "2.4.1", "wanted": "2.4.2", "latest": "5.3.0", "dependent": "other"},
+    {"current": "4.0.0", "wanted": "4.1.2", "latest": "5.3.0", "dependent": "app"}
+  ]
+}`,
+			map[string]*OutdatedInfo{
+				"chalk": {Current: "4.0.0", Wanted: "4.1.2", Latest: "5.3.0"},
+			},
+		},
+		{
+			"a dependency called error",
+			`{"error": {"current": "1.0.0", "wanted": "1.0.1", "latest": "2.0.0", "dependent": "app"}}`,
+			map[string]*OutdatedInfo{
+				"error": {Current: "1.0.0", Wanted: "1.0.1", Latest: "2.0.0"},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			outdated, err := ParseOutdated(s.output, "app")
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expected, outdated)
+		})
+	}
+}
+
+func TestParseOutdatedError(t *testing.T) {
+	_, err := ParseOutdated(`{"error": {"code": "E401", "summary": "Unable to authenticate, need: Basic realm=\"acme\""}}`, "app")
+	assert.EqualError(t, err, `Unable to authenticate, need: Basic realm="acme"`)
+
+	_, err = ParseOutdated(`{"error": {"code": "ENOTFOUND"}}`, "app")
+	assert.EqualError(t, err, "ENOTFOUND")
+}
@@ -16,19 +16,23 @@ type PackageConfig struct {
 	Description          string
 	Homepage             string
 	Main                 string
-	Deprecated           bool
+	PackageManager       string
+	Deprecated           string
 	Private              bool
 	Files                []string
 	Keywords             []string
 	Os                   []string
 	Cpu                  []string
 	BundledDependencies  []string
+	Workspaces           []string
 	Scripts              map[string]string
 	Directories          map[string]string
 	Dependencies         map[string]string
 	DevDependencies      map[string]string
 	PeerDependencies     map[string]string
 	OptionalDependencies map[string]string
+	PeerDependenciesMeta map[string]PeerDependencyMeta
+	Bin                  map[string]string
 	SortedDependencies   []*Dependency
 	Engines              struct {
 		Node string
@@ -42,6 +46,13 @@ type PackageConfig struct {
 	}
 }
 
+// PeerDependencyMeta is an entry in a package's peerDependenciesMeta
+type PeerDependencyMeta struct {
+	// Optional peers don't need to be installed, but if they are, they need to
+	// satisfy the constraint
+	Optional bool
+}
+
 type Author struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
@@ -83,6 +94,9 @@ type Package struct {
 	Path   string
 	// for when something is linked to the global node_modules folder
 	LinkedGlobally bool
+	PackageManager PackageManager
+	// if this package is a member of a workspace, this is the path of the workspace's root package
+	WorkspaceRootPath string
 }
 
 func (p *Package) SortedDependencies(previousDeps []*Dependency) []*Dependency {
@@ -163,6 +177,10 @@ func (p *Package) ID() string {
 	return fmt.Sprintf("package:%s", p.Path)
 }
 
+func (p *Package) IsWorkspaceMember() bool {
+	return p.WorkspaceRootPath != ""
+}
+
 func (p *Package) Scoped() bool {
 	return strings.HasPrefix(p.Config.Name, "@")
 }
@@ -0,0 +1,393 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// PackageJSON lets us edit a package.json file without disturbing anything we
+// don't touch: indentation, key order, and the trailing newline all survive.
+// We do this by splicing the raw bytes rather than re-encoding the whole file.
+type PackageJSON struct {
+	data []byte
+	// indent is one level of indentation e.g. two spaces. Empty if the file is
+	// all on one line
+	indent string
+	// colon is what goes between a key and its value e.g. ': '
+	colon string
+	// lineEnding is '\r\n' if the file uses windows line endings
+	lineEnding string
+}
+
+// jsonMember is a key/value pair in an object, with offsets into the raw bytes
+type jsonMember struct {
+	key        string
+	keyStart   int
+	keyEnd     int
+	valueStart int
+	valueEnd   int
+}
+
+// jsonObject is an object's members along with offsets of its braces
+type jsonObject struct {
+	open    int
+	close   int
+	members []jsonMember
+	// depth is 0 for the root object
+	depth int
+}
+
+func (o *jsonObject) member(key string) *jsonMember {
+	for i := range o.members {
+		if o.members[i].key == key {
+			return &o.members[i]
+		}
+	}
+	return nil
+}
+
+func NewPackageJSON(data []byte) (*PackageJSON, error) {
+	p := &PackageJSON{data: data, indent: "  ", colon: ": ", lineEnding: "\n"}
+	if bytes.Contains(data, []byte("\r\n")) {
+		p.lineEnding = "\r\n"
+	}
+
+	root, err := p.parseObject(skipWhitespace(data, 0), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// matching whatever formatting the first key uses
+	if len(root.members) > 0 {
+		first := root.members[0]
+		separator := string(data[root.open+1 : first.keyStart])
+		if idx := strings.LastIndex(separator, "\n"); idx != -1 {
+			p.indent = separator[idx+1:]
+		} else {
+			p.indent = ""
+		}
+		p.colon = string(data[first.keyEnd:first.valueStart])
+	}
+
+	return p, nil
+}
+
+func (p *PackageJSON) Bytes() []byte {
+	return p.data
+}
+
+// SetString sets the string at the given path, creating any objects on the
+// way that don't exist. New keys go at the end of their object.
+func (p *PackageJSON) SetString(value string, keys ...string) error {
+	return p.set(jsonString(value), false, keys)
+}
+
+// SetDependency sets a dependency's constraint. New dependencies are inserted
+// in alphabetical order, as npm would do.
+func (p *PackageJSON) SetDependency(kindKey string, name string, constraint string) error {
+	return p.set(jsonString(constraint), true, []string{kindKey, name})
+}
+
+// Delete removes the value at the given path. It's not an error if it doesn't exist.
+func (p *PackageJSON) Delete(keys ...string) error {
+	object, err := p.findObject(keys[:len(keys)-1])
+	if err != nil || object == nil {
+		return err
+	}
+
+	for i, member := range object.members {
+		if member.key != keys[len(keys)-1] {
+			continue
+		}
+
+		var start, end int
+		switch {
+		case len(object.members) == 1:
+			start, end = object.open+1, object.close
+		case i == 0:
+			// taking the comma and whatever space follows it
+			start, end = member.keyStart, object.members[1].keyStart
+		default:
+			start, end = object.members[i-1].valueEnd, member.valueEnd
+		}
+		p.splice(start, end, "")
+		return nil
+	}
+
+	return nil
+}
+
+// RenameKey changes the name of a key, leaving it where it is
+func (p *PackageJSON) RenameKey(newName string, keys ...string) error {
+	object, err := p.findObject(keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	if object == nil || object.member(keys[len(keys)-1]) == nil {
+		return errors.New("could not find " + strings.Join(keys, "."))
+	}
+	if object.member(newName) != nil {
+		return errors.New(newName + " already exists")
+	}
+
+	member := object.member(keys[len(keys)-1])
+	p.splice(member.keyStart, member.keyEnd, jsonString(newName))
+	return nil
+}
+
+// Has tells us whether the given path exists
+func (p *PackageJSON) Has(keys ...string) bool {
+	object, err := p.findObject(keys[:len(keys)-1])
+	return err == nil && object != nil && object.member(keys[len(keys)-1]) != nil
+}
+
+func (p *PackageJSON) set(value string, sorted bool, keys []string) error {
+	// finding the deepest object on the path that already exists
+	object, err := p.findObject(nil)
+	if err != nil {
+		return err
+	}
+	i := 0
+	for ; i < len(keys)-1; i++ {
+		member := object.member(keys[i])
+		if member == nil {
+			break
+		}
+		if p.data[member.valueStart] != '{' {
+			return errors.New(strings.Join(keys[:i+1], ".") + " is not an object")
+		}
+		object, err = p.parseObject(member.valueStart, object.depth+1)
+		if err != nil {
+			return err
+		}
+	}
+
+	if member := object.member(keys[i]); member != nil && i == len(keys)-1 {
+		p.splice(member.valueStart, member.valueEnd, value)
+		return nil
+	}
+
+	// any remaining keys need objects creating for them
+	for j := len(keys) - 1; j > i; j-- {
+		value = p.objectString(keys[j], value, object.depth+j-i)
+	}
+	// only the key itself is sorted, new parent objects go at the end
+	p.insert(object, keys[i], value, sorted && i == len(keys)-1)
+	return nil
+}
+
+// insert adds a new key to an object which doesn't already have it
+func (p *PackageJSON) insert(object *jsonObject, key string, value string, sorted bool) {
+	entry := jsonString(key) + p.colon + value
+
+	if len(object.members) == 0 {
+		p.splice(object.open+1, object.close, p.newline(object.depth+1)+entry+p.newline(object.depth))
+		return
+	}
+
+	if sorted {
+		for i, member := range object.members {
+			if member.key > key {
+				// reusing the whitespace that comes before the existing key
+				separatorStart := object.open + 1
+				if i > 0 {
+					previousEnd := object.members[i-1].valueEnd
+					separatorStart = previousEnd + bytes.IndexByte(p.data[previousEnd:], ',') + 1
+				}
+				separator := string(p.data[separatorStart:member.keyStart])
+				p.splice(member.keyStart, member.keyStart, entry+","+separator)
+				return
+			}
+		}
+	}
+
+	last := object.members[len(object.members)-1]
+	p.splice(last.valueEnd, last.valueEnd, ","+p.newline(object.depth+1)+entry)
+}
+
+// objectString renders a new object with a single key at the given depth
+func (p *PackageJSON) objectString(key string, value string, depth int) string {
+	return "{" + p.newline(depth+1) + jsonString(key) + p.colon + value + p.newline(depth) + "}"
+}
+
+func (p *PackageJSON) newline(depth int) string {
+	if p.indent == "" {
+		return ""
+	}
+	return p.lineEnding + strings.Repeat(p.indent, depth)
+}
+
+func (p *PackageJSON) splice(start int, end int, replacement string) {
+	data := make([]byte, 0, len(p.data)-(end-start)+len(replacement))
+	data = append(data, p.data[:start]...)
+	data = append(data, replacement...)
+	data = append(data, p.data[end:]...)
+	p.data = data
+}
+
+// findObject returns nil if there's nothing at the given path
+func (p *PackageJSON) findObject(keys []string) (*jsonObject, error) {
+	object, err := p.parseObject(skipWhitespace(p.data, 0), 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		member := object.member(key)
+		if member == nil {
+			return nil, nil
+		}
+		if p.data[member.valueStart] != '{' {
+			return nil, errors.New(key + " is not an object")
+		}
+		object, err = p.parseObject(member.valueStart, object.depth+1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return object, nil
+}
+
+func (p *PackageJSON) parseObject(start int, depth int) (*jsonObject, error) {
+	data := p.data
+	if start >= len(data) || data[start] != '{' {
+		return nil, errors.New("expected an object in package.json")
+	}
+
+	object := &jsonObject{open: start, depth: depth}
+	i := skipWhitespace(data, start+1)
+	if i < len(data) && data[i] == '}' {
+		object.close = i
+		return object, nil
+	}
+
+	for {
+		keyStart := i
+		keyEnd, err := scanJSONValue(data, keyStart)
+		if err != nil {
+			return nil, err
+		}
+		if data[keyStart] != '"' {
+			return nil, errors.New("expected a key in package.json")
+		}
+		var key string
+		if err := json.Unmarshal(data[keyStart:keyEnd], &key); err != nil {
+			return nil, err
+		}
+
+		i = skipWhitespace(data, keyEnd)
+		if i >= len(data) || data[i] != ':' {
+			return nil, errors.New("expected ':' in package.json")
+		}
+		valueStart := skipWhitespace(data, i+1)
+		valueEnd, err := scanJSONValue(data, valueStart)
+		if err != nil {
+			return nil, err
+		}
+		object.members = append(object.members, jsonMember{key: key, keyStart: keyStart, keyEnd: keyEnd, valueStart: valueStart, valueEnd: valueEnd})
+
+		i = skipWhitespace(data, valueEnd)
+		if i >= len(data) {
+			return nil, errors.New("unexpected end of package.json")
+		}
+		if data[i] == '}' {
+			object.close = i
+			return object, nil
+		}
+		if data[i] != ',' {
+			return nil, errors.New("expected ',' or '}' in package.json")
+		}
+		i = skipWhitespace(data, i+1)
+	}
+}
+
+// scanJSONValue returns the offset just after the value starting at the given offset
+func scanJSONValue(data []byte, start int) (int, error) {
+	if start >= len(data) {
+		return 0, errors.New("unexpected end of package.json")
+	}
+
+	switch data[start] {
+	case '"':
+		for i := start + 1; i < len(data); i++ {
+			switch data[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1, nil
+			}
+		}
+		return 0, errors.New("unterminated string in package.json")
+	case '{', '[':
+		depth := 0
+		for i := start; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				end, err := scanJSONValue(data, i)
+				if err != nil {
+					return 0, err
+				}
+				i = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1, nil
+				}
+			}
+		}
+		return 0, errors.New("unterminated value in package.json")
+	default:
+		// numbers, booleans, and null
+		i := start
+		for i < len(data) && !bytes.ContainsRune([]byte(",}] \t\r\n"), rune(data[i])) {
+			i++
+		}
+		return i, nil
+	}
+}
+
+func skipWhitespace(data []byte, i int) int {
+	for i < len(data) && bytes.ContainsRune([]byte(" \t\r\n"), rune(data[i])) {
+		i++
+	}
+	return i
+}
+
+// jsonString quotes a string for json without escaping characters like '&'
+// which are common in scripts
+func jsonString(str string) string {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(str)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// EditPackageJSON reads the package.json at the given path, applies the given
+// edit, and writes it back
+func (m *NpmManager) EditPackageJSON(packageJsonPath string, edit func(*PackageJSON) error) error {
+	fileInfo, err := os.Stat(packageJsonPath)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(packageJsonPath)
+	if err != nil {
+		return err
+	}
+
+	packageJSON, err := NewPackageJSON(data)
+	if err != nil {
+		return err
+	}
+	if err := edit(packageJSON); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(packageJsonPath, packageJSON.Bytes(), fileInfo.Mode())
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPackageJSON compares the result of each edit against the golden file of
+// the same name in testfiles/package_json/golden
+func TestPackageJSON(t *testing.T) {
+	type scenario struct {
+		name  string
+		input string
+		edit  func(*PackageJSON) error
+	}
+
+	scenarios := []scenario{
+		{
+			"edit_constraint",
+			"base.json",
+			func(p *PackageJSON) error { return p.SetDependency("dependencies", "lodash", "^4.17.20") },
+		},
+		{
+			"add_dependency_sorted",
+			"base.json",
+			func(p *PackageJSON) error {
+				for _, name := range []string{"axios", "express", "zod"} {
+					if err := p.SetDependency("dependencies", name, "^1.0.0"); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			"add_dependency_missing_object",
+			"base.json",
+			func(p *PackageJSON) error { return p.SetDependency("devDependencies", "jest", "^26.0.0") },
+		},
+		{
+			"add_script",
+			"base.json",
+			func(p *PackageJSON) error { return p.SetString(`echo "<done>" && exit 0`, "scripts", "lint") },
+		},
+		{
+			"rename_script",
+			"base.json",
+			func(p *PackageJSON) error {
+				if err := p.RenameKey("unit", "scripts", "test"); err != nil {
+					return err
+				}
+				return p.SetString("jest", "scripts", "unit")
+			},
+		},
+		{
+			"remove_first_script",
+			"base.json",
+			func(p *PackageJSON) error { return p.Delete("scripts", "build") },
+		},
+		{
+			"remove_last_script",
+			"base.json",
+			func(p *PackageJSON) error { return p.Delete("scripts", "watch") },
+		},
+		{
+			"remove_missing_script",
+			"base.json",
+			func(p *PackageJSON) error { return p.Delete("scripts", "nonexistent") },
+		},
+		{
+			"add_script_to_empty_object",
+			"tabs.json",
+			func(p *PackageJSON) error { return p.SetString("tsc", "scripts", "build") },
+		},
+		{
+			"remove_only_dependency",
+			"tabs.json",
+			func(p *PackageJSON) error { return p.Delete("devDependencies", "typescript") },
+		},
+		{
+			"add_nested_objects",
+			"tabs.json",
+			func(p *PackageJSON) error { return p.SetString("^1.0.0", "publishConfig", "tags", "next") },
+		},
+		{
+			"add_dependency_minified",
+			"minified.json",
+			func(p *PackageJSON) error { return p.SetDependency("dependencies", "left-pad", "^1.3.0") },
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			input, err := ioutil.ReadFile(filepath.Join("testfiles", "package_json", s.input))
+			assert.NoError(t, err)
+			expected, err := ioutil.ReadFile(filepath.Join("testfiles", "package_json", "golden", s.name+".json"))
+			assert.NoError(t, err)
+
+			packageJSON, err := NewPackageJSON(input)
+			assert.NoError(t, err)
+			assert.NoError(t, s.edit(packageJSON))
+			assert.EqualValues(t, string(expected), string(packageJSON.Bytes()))
+		})
+	}
+}
+
+func TestPackageJSONLineEndings(t *testing.T) {
+	packageJSON, err := NewPackageJSON([]byte("{\r\n  \"name\": \"a\"\r\n}\r\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, packageJSON.SetDependency("dependencies", "b", "^1.0.0"))
+	assert.EqualValues(t, "{\r\n  \"name\": \"a\",\r\n  \"dependencies\": {\r\n    \"b\": \"^1.0.0\"\r\n  }\r\n}\r\n", string(packageJSON.Bytes()))
+}
+
+func TestPackageJSONErrors(t *testing.T) {
+	_, err := NewPackageJSON([]byte(`["not", "an", "object"]`))
+	assert.Error(t, err)
+
+	packageJSON, err := NewPackageJSON([]byte(`{"name": "a", "scripts": {"a": "b", "c": "d"}}`))
+	assert.NoError(t, err)
+	assert.Error(t, packageJSON.SetString("x", "name", "nested"))
+	assert.Error(t, packageJSON.RenameKey("c", "scripts", "a"))
+	assert.Error(t, packageJSON.RenameKey("e", "scripts", "nonexistent"))
+}
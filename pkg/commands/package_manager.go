@@ -0,0 +1,507 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// PackageManager builds the command strings for a particular package manager
+// (npm, yarn, or pnpm) so that the gui doesn't need to care which one a package
+// is using.
+type PackageManager interface {
+	Name() string
+	// Lockfiles are the files whose presence tells us a package is using this package manager
+	Lockfiles() []string
+	// GlobalLinkDir returns the directory in which globally linked packages end up
+	GlobalLinkDir(osCommand *OSCommand) (string, error)
+
+	Install(opts CmdOpts) string
+	Update(opts CmdOpts) string
+	RunScript(scriptName string, opts CmdOpts) string
+	Pack(opts CmdOpts) string
+	Publish(opts PublishOpts) string
+	// Outdated returns an empty string if the package manager can't give us
+	// outdated dependencies as json in the format npm uses
+	Outdated(opts CmdOpts) string
+	// Audit returns an empty string if the package manager can't give us an
+	// audit report as json in one of the formats npm uses
+	Audit(opts CmdOpts) string
+	// AuditFix returns an empty string if the package manager can't fix
+	// vulnerabilities (or can't force a fix, if force is true)
+	AuditFix(force bool, opts CmdOpts) string
+	// Dedupe returns an empty string if the package manager can't dedupe
+	Dedupe(opts CmdOpts) string
+	// Version sets the package's version, committing and tagging it in git
+	// unless gitTag is false. Returns an empty string if the package manager
+	// has no version command
+	Version(version string, gitTag bool, opts CmdOpts) string
+	// DistTagAdd points a dist-tag at the given version of a published package,
+	// adding the tag if it doesn't exist
+	DistTagAdd(name string, version string, tag string) string
+	DistTagRemove(name string, tag string) string
+
+	// Link links the given package into the current package. If the package has
+	// already been globally linked we can link it by name alone. Returns an
+	// empty string if the package manager can't link the package as it is
+	Link(name string, path string, linkedGlobally bool) string
+	Unlink(name string) string
+	GlobalLink() string
+	GlobalUnlink(name string) string
+
+	// AddDeps installs dependencies and saves them against the given kind (prod/dev/optional/peer).
+	// An empty kind means we leave it up to the package manager
+	AddDeps(kind string, names ...string) string
+	UpdateDeps(names ...string) string
+	RemoveDeps(kind string, names ...string) string
+	// RemoveDepsWithoutSaving returns an empty string if the package manager
+	// has no way of uninstalling without updating package.json
+	RemoveDepsWithoutSaving(names ...string) string
+	InstallTarball(path string) string
+}
+
+// CmdOpts tells a package manager which package to run a command against
+type CmdOpts struct {
+	// Prefix is the path of the package we're targeting. If blank, the command
+	// runs against the current package. If Workspaces is set, this is the path
+	// of the workspace root.
+	Prefix string
+	// Workspaces are the names of the workspace members to scope the command
+	// to. Package managers that can only target one workspace at a time return
+	// an empty string when given several
+	Workspaces []string
+}
+
+type PublishOpts struct {
+	// Target is the folder or tarball to publish
+	Target string
+	Access string
+	Tag    string
+}
+
+func PackageManagers() []PackageManager {
+	return []PackageManager{&Npm{}, &Yarn{}, &Pnpm{}}
+}
+
+// PackageManagerByName returns nil if there is no package manager with the given name
+func PackageManagerByName(name string) PackageManager {
+	for _, pm := range PackageManagers() {
+		if pm.Name() == name {
+			return pm
+		}
+	}
+	return nil
+}
+
+// DetectPackageManager works out which package manager a package uses, first
+// by looking at the `packageManager` field in package.json (e.g. "pnpm@8.6.0")
+// and then by looking for a lockfile. Defaults to npm.
+func DetectPackageManager(path string, pkgConfig *PackageConfig) PackageManager {
+	if pkgConfig != nil && pkgConfig.PackageManager != "" {
+		name := strings.SplitN(pkgConfig.PackageManager, "@", 2)[0]
+		if pm := PackageManagerByName(name); pm != nil {
+			return pm
+		}
+	}
+
+	for _, pm := range PackageManagers() {
+		for _, lockfile := range pm.Lockfiles() {
+			if FileExists(filepath.Join(path, lockfile)) {
+				return pm
+			}
+		}
+	}
+
+	return &Npm{}
+}
+
+func joinArgs(args ...string) string {
+	nonEmptyArgs := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg != "" {
+			nonEmptyArgs = append(nonEmptyArgs, arg)
+		}
+	}
+	return strings.Join(nonEmptyArgs, " ")
+}
+
+func flagIf(condition bool, flag string) string {
+	if condition {
+		return flag
+	}
+	return ""
+}
+
+// quotePath wraps a path in quotes if it needs them to survive being split into
+// args by OSCommand.ExecutableFromString e.g. when it contains a space
+func quotePath(path string) string {
+	specialChars := " \t\"'"
+	if runtime.GOOS != "windows" {
+		specialChars += `\`
+	}
+	if !strings.ContainsAny(path, specialChars) {
+		return path
+	}
+	if runtime.GOOS != "windows" {
+		path = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(path)
+	}
+	return `"` + path + `"`
+}
+
+// pathFlag passes the flag with the given path as its own arg
+func pathFlag(flag string, path string) string {
+	if path == "" {
+		return ""
+	}
+	return flag + " " + quotePath(path)
+}
+
+func flagWithValue(flag string, value string) string {
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s=%s", flag, value)
+}
+
+type Npm struct{}
+
+func (*Npm) Name() string { return "npm" }
+
+func (*Npm) Lockfiles() []string { return []string{"npm-shrinkwrap.json", "package-lock.json"} }
+
+func (*Npm) GlobalLinkDir(osCommand *OSCommand) (string, error) {
+	output, err := osCommand.RunCommandWithOutput("npm root -g")
+	return strings.TrimSpace(output), err
+}
+
+// repeatedFlag passes the flag once for each value
+func repeatedFlag(flag string, values []string) string {
+	args := make([]string, len(values))
+	for i, value := range values {
+		args[i] = flag + value
+	}
+	return joinArgs(args...)
+}
+
+func npmFlags(opts CmdOpts) string {
+	return joinArgs(repeatedFlag("--workspace=", opts.Workspaces), pathFlag("--prefix", opts.Prefix))
+}
+
+func (*Npm) Install(opts CmdOpts) string {
+	return joinArgs("npm install", npmFlags(opts))
+}
+
+func (*Npm) Update(opts CmdOpts) string {
+	return joinArgs("npm update", npmFlags(opts))
+}
+
+func (*Npm) RunScript(scriptName string, opts CmdOpts) string {
+	return joinArgs("npm run", scriptName, npmFlags(opts))
+}
+
+func (*Npm) Pack(opts CmdOpts) string {
+	if len(opts.Workspaces) > 0 {
+		return joinArgs("npm pack", npmFlags(opts))
+	}
+	return joinArgs("npm pack", quotePath(opts.Prefix))
+}
+
+func (*Npm) Publish(opts PublishOpts) string {
+	return joinArgs("npm publish", flagWithValue("--access", opts.Access), flagWithValue("--tag", opts.Tag), quotePath(opts.Target))
+}
+
+func (*Npm) Outdated(opts CmdOpts) string {
+	return joinArgs("npm outdated --json", npmFlags(opts))
+}
+
+func (*Npm) Audit(opts CmdOpts) string {
+	return joinArgs("npm audit --json", npmFlags(opts))
+}
+
+func (*Npm) AuditFix(force bool, opts CmdOpts) string {
+	return joinArgs("npm audit fix", flagIf(force, "--force"), npmFlags(opts))
+}
+
+func (*Npm) Dedupe(opts CmdOpts) string {
+	return joinArgs("npm dedupe", npmFlags(opts))
+}
+
+func (*Npm) Version(version string, gitTag bool, opts CmdOpts) string {
+	return joinArgs("npm version", version, flagIf(!gitTag, "--no-git-tag-version"), npmFlags(opts))
+}
+
+func (*Npm) DistTagAdd(name string, version string, tag string) string {
+	return joinArgs("npm dist-tag add", name+"@"+version, tag)
+}
+
+func (*Npm) DistTagRemove(name string, tag string) string {
+	return joinArgs("npm dist-tag rm", name, tag)
+}
+
+func (*Npm) Link(name string, path string, linkedGlobally bool) string {
+	if linkedGlobally {
+		return joinArgs("npm link", name)
+	}
+	return joinArgs("npm link", quotePath(path))
+}
+
+func (*Npm) Unlink(name string) string { return joinArgs("npm unlink --no-save", name) }
+
+func (*Npm) GlobalLink() string { return "npm link" }
+
+func (*Npm) GlobalUnlink(name string) string { return "npm unlink" }
+
+func (*Npm) AddDeps(kind string, names ...string) string {
+	return joinArgs("npm install", KindFlagMap()[kind], strings.Join(names, " "))
+}
+
+func (*Npm) UpdateDeps(names ...string) string {
+	return joinArgs("npm update", strings.Join(names, " "))
+}
+
+func (*Npm) RemoveDeps(kind string, names ...string) string {
+	flag := map[string]string{
+		"prod":     "--save",
+		"dev":      "--save-dev",
+		"optional": "--save-optional",
+	}[kind]
+	return joinArgs("npm uninstall", flag, strings.Join(names, " "))
+}
+
+func (*Npm) RemoveDepsWithoutSaving(names ...string) string {
+	return joinArgs("npm uninstall --no-save", strings.Join(names, " "))
+}
+
+func (*Npm) InstallTarball(path string) string { return joinArgs("npm install", quotePath(path)) }
+
+// global packages live in npm's global root so we always manage them with npm,
+// whichever package manager the current package uses
+
+func (*Npm) GlobalInstall(names ...string) string {
+	return joinArgs("npm install -g", strings.Join(names, " "))
+}
+
+func (*Npm) GlobalUpdate(names ...string) string {
+	return joinArgs("npm update -g", strings.Join(names, " "))
+}
+
+func (*Npm) GlobalUninstall(names ...string) string {
+	return joinArgs("npm uninstall -g", strings.Join(names, " "))
+}
+
+func (*Npm) GlobalOutdated() string { return "npm outdated -g --json" }
+
+type Yarn struct{}
+
+func (*Yarn) Name() string { return "yarn" }
+
+func (*Yarn) Lockfiles() []string { return []string{"yarn.lock"} }
+
+// yarn keeps its links in a 'link' directory alongside its global directory
+func (*Yarn) GlobalLinkDir(osCommand *OSCommand) (string, error) {
+	output, err := osCommand.RunCommandWithOutput("yarn global dir")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(strings.TrimSpace(output)), "link"), nil
+}
+
+func yarnFlags(opts CmdOpts) string {
+	workspace := ""
+	if len(opts.Workspaces) > 0 {
+		workspace = "workspace " + opts.Workspaces[0]
+	}
+	return joinArgs(pathFlag("--cwd", opts.Prefix), workspace)
+}
+
+// yarn's workspace command only takes one workspace
+func yarnTargetsOneWorkspace(opts CmdOpts) bool {
+	return len(opts.Workspaces) <= 1
+}
+
+// yarn installs all workspaces from the root in one go
+func (*Yarn) Install(opts CmdOpts) string {
+	return joinArgs("yarn", pathFlag("--cwd", opts.Prefix), "install")
+}
+
+func (*Yarn) Update(opts CmdOpts) string {
+	if !yarnTargetsOneWorkspace(opts) {
+		return ""
+	}
+	return joinArgs("yarn", yarnFlags(opts), "upgrade")
+}
+
+func (*Yarn) RunScript(scriptName string, opts CmdOpts) string {
+	if !yarnTargetsOneWorkspace(opts) {
+		return ""
+	}
+	return joinArgs("yarn", yarnFlags(opts), "run", scriptName)
+}
+
+func (*Yarn) Pack(opts CmdOpts) string {
+	if !yarnTargetsOneWorkspace(opts) {
+		return ""
+	}
+	return joinArgs("yarn", yarnFlags(opts), "pack")
+}
+
+func (*Yarn) Publish(opts PublishOpts) string {
+	return joinArgs("yarn publish", quotePath(opts.Target), flagIf(opts.Access != "", "--access "+opts.Access), flagIf(opts.Tag != "", "--tag "+opts.Tag))
+}
+
+// yarn's json output is a stream of table rows rather than an object keyed by dependency
+func (*Yarn) Outdated(opts CmdOpts) string { return "" }
+
+// yarn's json audit output is a stream of individual advisories
+func (*Yarn) Audit(opts CmdOpts) string { return "" }
+
+func (*Yarn) AuditFix(force bool, opts CmdOpts) string { return "" }
+
+// yarn 1 removed its dedupe command, saying installs are already deduped
+func (*Yarn) Dedupe(opts CmdOpts) string { return "" }
+
+func (*Yarn) Version(version string, gitTag bool, opts CmdOpts) string {
+	if !yarnTargetsOneWorkspace(opts) {
+		return ""
+	}
+	return joinArgs("yarn", yarnFlags(opts), "version --new-version", version, flagIf(!gitTag, "--no-git-tag-version"))
+}
+
+func (*Yarn) DistTagAdd(name string, version string, tag string) string {
+	return joinArgs("yarn tag add", name+"@"+version, tag)
+}
+
+func (*Yarn) DistTagRemove(name string, tag string) string {
+	return joinArgs("yarn tag remove", name, tag)
+}
+
+// yarn can only link packages which have already been linked globally via `yarn link`
+func (*Yarn) Link(name string, path string, linkedGlobally bool) string {
+	if !linkedGlobally {
+		return ""
+	}
+	return joinArgs("yarn link", name)
+}
+
+func (*Yarn) Unlink(name string) string { return joinArgs("yarn unlink", name) }
+
+func (*Yarn) GlobalLink() string { return "yarn link" }
+
+func (*Yarn) GlobalUnlink(name string) string { return "yarn unlink" }
+
+func (*Yarn) AddDeps(kind string, names ...string) string {
+	flag := map[string]string{
+		"dev":      "--dev",
+		"optional": "--optional",
+		"peer":     "--peer",
+	}[kind]
+	return joinArgs("yarn add", flag, strings.Join(names, " "))
+}
+
+func (*Yarn) UpdateDeps(names ...string) string {
+	return joinArgs("yarn upgrade", strings.Join(names, " "))
+}
+
+func (*Yarn) RemoveDeps(kind string, names ...string) string {
+	return joinArgs("yarn remove", strings.Join(names, " "))
+}
+
+func (*Yarn) RemoveDepsWithoutSaving(names ...string) string { return "" }
+
+func (*Yarn) InstallTarball(path string) string { return joinArgs("yarn add", quotePath(path)) }
+
+type Pnpm struct{}
+
+func (*Pnpm) Name() string { return "pnpm" }
+
+func (*Pnpm) Lockfiles() []string { return []string{"pnpm-lock.yaml"} }
+
+func (*Pnpm) GlobalLinkDir(osCommand *OSCommand) (string, error) {
+	output, err := osCommand.RunCommandWithOutput("pnpm root -g")
+	return strings.TrimSpace(output), err
+}
+
+func pnpmDirFlag(opts CmdOpts) string {
+	return joinArgs(pathFlag("--dir", opts.Prefix), repeatedFlag("--filter ", opts.Workspaces))
+}
+
+func (*Pnpm) Install(opts CmdOpts) string {
+	return joinArgs("pnpm", pnpmDirFlag(opts), "install")
+}
+
+func (*Pnpm) Update(opts CmdOpts) string {
+	return joinArgs("pnpm", pnpmDirFlag(opts), "update")
+}
+
+func (*Pnpm) RunScript(scriptName string, opts CmdOpts) string {
+	return joinArgs("pnpm", pnpmDirFlag(opts), "run", scriptName)
+}
+
+func (*Pnpm) Pack(opts CmdOpts) string {
+	return joinArgs("pnpm", pnpmDirFlag(opts), "pack")
+}
+
+func (*Pnpm) Publish(opts PublishOpts) string {
+	return joinArgs("pnpm publish", quotePath(opts.Target), flagWithValue("--access", opts.Access), flagWithValue("--tag", opts.Tag))
+}
+
+func (*Pnpm) Outdated(opts CmdOpts) string {
+	return joinArgs("pnpm", pnpmDirFlag(opts), "outdated --format json")
+}
+
+// pnpm gives us the same report format as npm 6
+func (*Pnpm) Audit(opts CmdOpts) string {
+	return joinArgs("pnpm", pnpmDirFlag(opts), "audit --json")
+}
+
+func (*Pnpm) AuditFix(force bool, opts CmdOpts) string {
+	if force {
+		return ""
+	}
+	return joinArgs("pnpm", pnpmDirFlag(opts), "audit --fix")
+}
+
+func (*Pnpm) Dedupe(opts CmdOpts) string {
+	return joinArgs("pnpm", pnpmDirFlag(opts), "dedupe")
+}
+
+// pnpm has no version command of its own
+func (*Pnpm) Version(version string, gitTag bool, opts CmdOpts) string { return "" }
+
+// pnpm has no dist-tag command either, but npm's works for any package given
+// tags live in the registry
+func (*Pnpm) DistTagAdd(name string, version string, tag string) string {
+	return (&Npm{}).DistTagAdd(name, version, tag)
+}
+
+func (*Pnpm) DistTagRemove(name string, tag string) string {
+	return (&Npm{}).DistTagRemove(name, tag)
+}
+
+// pnpm is happy to link straight from a directory
+func (*Pnpm) Link(name string, path string, linkedGlobally bool) string {
+	return joinArgs("pnpm link", quotePath(path))
+}
+
+func (*Pnpm) Unlink(name string) string { return joinArgs("pnpm unlink", name) }
+
+func (*Pnpm) GlobalLink() string { return "pnpm link --global" }
+
+func (*Pnpm) GlobalUnlink(name string) string { return joinArgs("pnpm remove --global", name) }
+
+func (*Pnpm) AddDeps(kind string, names ...string) string {
+	return joinArgs("pnpm add", KindFlagMap()[kind], strings.Join(names, " "))
+}
+
+func (*Pnpm) UpdateDeps(names ...string) string {
+	return joinArgs("pnpm update", strings.Join(names, " "))
+}
+
+func (*Pnpm) RemoveDeps(kind string, names ...string) string {
+	return joinArgs("pnpm remove", strings.Join(names, " "))
+}
+
+func (*Pnpm) RemoveDepsWithoutSaving(names ...string) string { return "" }
+
+func (*Pnpm) InstallTarball(path string) string { return joinArgs("pnpm add", quotePath(path)) }
@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectPackageManager(t *testing.T) {
+	type scenario struct {
+		name         string
+		lockfiles    []string
+		pkgConfig    *PackageConfig
+		expectedName string
+	}
+
+	scenarios := []scenario{
+		{
+			name:         "defaults to npm",
+			lockfiles:    nil,
+			pkgConfig:    &PackageConfig{},
+			expectedName: "npm",
+		},
+		{
+			name:         "yarn lockfile",
+			lockfiles:    []string{"yarn.lock"},
+			pkgConfig:    &PackageConfig{},
+			expectedName: "yarn",
+		},
+		{
+			name:         "pnpm lockfile",
+			lockfiles:    []string{"pnpm-lock.yaml"},
+			pkgConfig:    &PackageConfig{},
+			expectedName: "pnpm",
+		},
+		{
+			name:         "packageManager field beats lockfile",
+			lockfiles:    []string{"package-lock.json"},
+			pkgConfig:    &PackageConfig{PackageManager: "pnpm@8.6.0"},
+			expectedName: "pnpm",
+		},
+		{
+			name:         "unknown packageManager field falls back to lockfile",
+			lockfiles:    []string{"yarn.lock"},
+			pkgConfig:    &PackageConfig{PackageManager: "bun@1.0.0"},
+			expectedName: "yarn",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lazynpm")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			for _, lockfile := range s.lockfiles {
+				assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, lockfile), []byte{}, 0644))
+			}
+
+			assert.EqualValues(t, s.expectedName, DetectPackageManager(dir, s.pkgConfig).Name())
+		})
+	}
+}
+
+func TestPackageManagerCommands(t *testing.T) {
+	type scenario struct {
+		pm       PackageManager
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			&Npm{},
+			[]string{"npm install --prefix /a", "npm run build", "npm install --save-dev foo bar", "npm uninstall --save-optional foo", "npm publish --access=public --tag=next /a", "npm version 1.2.3 --no-git-tag-version", "npm dist-tag add foo@1.2.3 next", "npm dist-tag rm foo next", "npm link /a", "npm link foo"},
+		},
+		{
+			&Yarn{},
+			[]string{"yarn --cwd /a install", "yarn run build", "yarn add --dev foo bar", "yarn remove foo", "yarn publish /a --access public --tag next", "yarn version --new-version 1.2.3 --no-git-tag-version", "yarn tag add foo@1.2.3 next", "yarn tag remove foo next", "", "yarn link foo"},
+		},
+		{
+			&Pnpm{},
+			[]string{"pnpm --dir /a install", "pnpm run build", "pnpm add --save-dev foo bar", "pnpm remove foo", "pnpm publish /a --access=public --tag=next", "", "npm dist-tag add foo@1.2.3 next", "npm dist-tag rm foo next", "pnpm link /a", "pnpm link /a"},
+		},
+	}
+
+	for _, s := range scenarios {
+		assert.EqualValues(t, s.expected, []string{
+			s.pm.Install(CmdOpts{Prefix: "/a"}),
+			s.pm.RunScript("build", CmdOpts{}),
+			s.pm.AddDeps("dev", "foo", "bar"),
+			s.pm.RemoveDeps("optional", "foo"),
+			s.pm.Publish(PublishOpts{Target: "/a", Access: "public", Tag: "next"}),
+			s.pm.Version("1.2.3", false, CmdOpts{}),
+			s.pm.DistTagAdd("foo", "1.2.3", "next"),
+			s.pm.DistTagRemove("foo", "next"),
+			s.pm.Link("foo", "/a", false),
+			s.pm.Link("foo", "/a", true),
+		})
+	}
+}
+
+func TestPackageManagerWorkspaceCommands(t *testing.T) {
+	type scenario struct {
+		pm       PackageManager
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			&Npm{},
+			[]string{"npm run build --workspace=a --prefix /root", "npm run build --workspace=a --workspace=b --prefix /root", "npm pack --workspace=a --workspace=b", "npm install --workspace=a --workspace=b"},
+		},
+		{
+			&Yarn{},
+			[]string{"yarn --cwd /root workspace a run build", "", "", "yarn install"},
+		},
+		{
+			&Pnpm{},
+			[]string{"pnpm --dir /root --filter a run build", "pnpm --dir /root --filter a --filter b run build", "pnpm --filter a --filter b pack", "pnpm --filter a --filter b install"},
+		},
+	}
+
+	for _, s := range scenarios {
+		assert.EqualValues(t, s.expected, []string{
+			s.pm.RunScript("build", CmdOpts{Prefix: "/root", Workspaces: []string{"a"}}),
+			s.pm.RunScript("build", CmdOpts{Prefix: "/root", Workspaces: []string{"a", "b"}}),
+			s.pm.Pack(CmdOpts{Workspaces: []string{"a", "b"}}),
+			s.pm.Install(CmdOpts{Workspaces: []string{"a", "b"}}),
+		})
+	}
+}
+
+func TestPackageManagerQuotesPaths(t *testing.T) {
+	type scenario struct {
+		pm       PackageManager
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			&Npm{},
+			[]string{`npm install --prefix "/my projects/a"`, `npm pack "/my projects/a"`, `npm publish "/my projects/a"`, `npm install "/my projects/a.tgz"`},
+		},
+		{
+			&Yarn{},
+			[]string{`yarn --cwd "/my projects/a" install`, `yarn --cwd "/my projects/a" pack`, `yarn publish "/my projects/a"`, `yarn add "/my projects/a.tgz"`},
+		},
+		{
+			&Pnpm{},
+			[]string{`pnpm --dir "/my projects/a" install`, `pnpm --dir "/my projects/a" pack`, `pnpm publish "/my projects/a"`, `pnpm add "/my projects/a.tgz"`},
+		},
+	}
+
+	for _, s := range scenarios {
+		assert.EqualValues(t, s.expected, []string{
+			s.pm.Install(CmdOpts{Prefix: "/my projects/a"}),
+			s.pm.Pack(CmdOpts{Prefix: "/my projects/a"}),
+			s.pm.Publish(PublishOpts{Target: "/my projects/a"}),
+			s.pm.InstallTarball("/my projects/a.tgz"),
+		})
+	}
+}
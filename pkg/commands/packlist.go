@@ -0,0 +1,330 @@
+package commands
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LargePackFileSize is the size above which we warn about a file being published
+const LargePackFileSize = 1024 * 1024
+
+// PackFile is a file that would be included when publishing a package
+type PackFile struct {
+	// Path is relative to the package, with forward slashes
+	Path string
+	Size int64
+	// Warning explains why the file looks like it shouldn't be published
+	Warning string
+}
+
+type PackList struct {
+	Files     []*PackFile
+	TotalSize int64
+}
+
+// Warnings returns the files that look suspicious
+func (l *PackList) Warnings() []*PackFile {
+	files := []*PackFile{}
+	for _, file := range l.Files {
+		if file.Warning != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// these are never published, no matter what the package says
+var alwaysExcludedPatterns = []string{
+	".git",
+	"CVS",
+	".svn",
+	".hg",
+	".lock-wscript",
+	".wafpickle-*",
+	".*.swp",
+	".DS_Store",
+	"._*",
+	"npm-debug.log",
+	".npmrc",
+	"node_modules",
+	"config.gypi",
+	"*.orig",
+	"package-lock.json",
+	".npmignore",
+	".gitignore",
+}
+
+// these are always published from the package's root, no matter what the package says
+var alwaysIncludedPattern = regexp.MustCompile(`(?i)^(package\.json|readme(\..*)?|licen[cs]e(\..*)?|copying(\..*)?|changelog(\..*)?)$`)
+
+// ignoreRule is a line from a .gitignore-style file
+type ignoreRule struct {
+	regex   *regexp.Regexp
+	negated bool
+	dirOnly bool
+}
+
+// ignoreRules are the rules from one ignore file (or the `files` field),
+// relative to the directory the file is in
+type ignoreRules struct {
+	dir   string
+	rules []ignoreRule
+}
+
+// match returns whether the rules ignore the path and whether any rule matched at all
+func (r *ignoreRules) match(relPath string, isDir bool) (ignored bool, matched bool) {
+	if r.dir != "" {
+		if !strings.HasPrefix(relPath, r.dir+"/") {
+			return false, false
+		}
+		relPath = strings.TrimPrefix(relPath, r.dir+"/")
+	}
+	// as with git, the last matching rule wins
+	for _, rule := range r.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.regex.MatchString(relPath) {
+			ignored = !rule.negated
+			matched = true
+		}
+	}
+	return ignored, matched
+}
+
+func parseIgnoreRules(dir string, lines []string) *ignoreRules {
+	rules := &ignoreRules{dir: dir}
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := ignoreRule{}
+		if strings.HasPrefix(line, "!") {
+			rule.negated = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		rule.regex = globToRegexp(line)
+		rules.rules = append(rules.rules, rule)
+	}
+	return rules
+}
+
+// globToRegexp converts a gitignore-style glob. Patterns without a slash can
+// match at any depth, whereas patterns with one are relative to the ignore
+// file's directory
+func globToRegexp(pattern string) *regexp.Regexp {
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.Index(pattern[i:], "]")
+			if end == -1 {
+				sb.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	// a pattern matching a directory matches everything inside it too
+	sb.WriteString("(/.*)?$")
+
+	regex, err := regexp.Compile(sb.String())
+	if err != nil {
+		return regexp.MustCompile(regexp.QuoteMeta(pattern))
+	}
+	return regex
+}
+
+var alwaysExcludedRules = parseIgnoreRules("", alwaysExcludedPatterns)
+
+// GetPackList works out which files would be published, following npm's rules:
+// if the package has a `files` field only those files are included, otherwise
+// everything not ignored by a .npmignore (or failing that, a .gitignore) is
+// included. Either way, some files are always included or excluded.
+func (m *NpmManager) GetPackList(pkg *Package) (*PackList, error) {
+	var filesRules *ignoreRules
+	if len(pkg.Config.Files) > 0 {
+		// entries in `files` are relative to the package root
+		patterns := make([]string, len(pkg.Config.Files))
+		for i, pattern := range pkg.Config.Files {
+			if strings.HasPrefix(pattern, "!") {
+				patterns[i] = "!/" + strings.TrimPrefix(pattern[1:], "/")
+			} else {
+				patterns[i] = "/" + strings.TrimPrefix(pattern, "/")
+			}
+		}
+		filesRules = parseIgnoreRules("", patterns)
+	}
+	// npm publishes the package's main file and its bin files whatever the
+	// package's rules say
+	forcedPaths := map[string]bool{}
+	if pkg.Config.Main != "" {
+		forcedPaths[path.Clean(filepath.ToSlash(pkg.Config.Main))] = true
+	}
+	for _, bin := range pkg.Config.Bin {
+		forcedPaths[path.Clean(filepath.ToSlash(bin))] = true
+	}
+
+	list := &PackList{Files: []*PackFile{}}
+	err := walkPackage(pkg.Path, "", nil, filesRules != nil, func(relPath string, info os.FileInfo, ignoreStack []*ignoreRules) bool {
+		isDir := info.IsDir()
+		if ignored, _ := alwaysExcludedRules.match(relPath, isDir); ignored {
+			return false
+		}
+
+		isRootFile := !isDir && !strings.Contains(relPath, "/")
+		if (isRootFile && alwaysIncludedPattern.MatchString(relPath)) || (!isDir && forcedPaths[relPath]) {
+			list.add(relPath, info.Size())
+			return true
+		}
+
+		// ignore files further down the tree take precedence
+		for i := len(ignoreStack) - 1; i >= 0; i-- {
+			if ignored, matched := ignoreStack[i].match(relPath, isDir); matched {
+				if ignored {
+					return false
+				}
+				break
+			}
+		}
+
+		if isDir {
+			return true
+		}
+		if filesRules != nil {
+			// `files` is a list of things to include, so matching a rule means
+			// the file is in (unless the rule is negated)
+			included, matched := filesRules.match(relPath, false)
+			if !matched || !included {
+				return true
+			}
+		}
+		list.add(relPath, info.Size())
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list.Files, func(i, j int) bool { return list.Files[i].Path < list.Files[j].Path })
+	return list, nil
+}
+
+func (l *PackList) add(relPath string, size int64) {
+	l.Files = append(l.Files, &PackFile{Path: relPath, Size: size, Warning: packFileWarning(relPath, size)})
+	l.TotalSize += size
+}
+
+// walkPackage visits every file and folder in the package, picking up ignore
+// files as it goes. visit returns false to skip a folder
+func walkPackage(root string, relDir string, ignoreStack []*ignoreRules, skipRootIgnoreFiles bool, visit func(string, os.FileInfo, []*ignoreRules) bool) error {
+	dir := filepath.Join(root, filepath.FromSlash(relDir))
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	fileInfos, err := file.Readdir(-1)
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	// a package's `files` field takes the place of its root ignore files
+	if relDir != "" || !skipRootIgnoreFiles {
+		for _, name := range []string{".npmignore", ".gitignore"} {
+			lines, err := readLines(filepath.Join(dir, name))
+			if err != nil {
+				continue
+			}
+			ignoreStack = append(ignoreStack[:len(ignoreStack):len(ignoreStack)], parseIgnoreRules(relDir, lines))
+			break
+		}
+	}
+
+	for _, fileInfo := range fileInfos {
+		relPath := path.Join(relDir, fileInfo.Name())
+		if !visit(relPath, fileInfo, ignoreStack) || !fileInfo.IsDir() {
+			continue
+		}
+		if err := walkPackage(root, relPath, ignoreStack, skipRootIgnoreFiles, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	lines := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+var suspiciousFilePatterns = []struct {
+	regex   *regexp.Regexp
+	warning string
+}{
+	{regexp.MustCompile(`(^|/)\.env(\..*)?$`), "environment file, may contain secrets"},
+	{regexp.MustCompile(`(^|/)(id_rsa|id_dsa|id_ecdsa|id_ed25519)$|\.(pem|key|p12|pfx)$`), "looks like a private key"},
+	{regexp.MustCompile(`(^|/)(__tests__|__mocks__|__fixtures__|fixtures|coverage|\.nyc_output)/`), "test fixtures or coverage output"},
+	{regexp.MustCompile(`\.(test|spec)\.[jt]sx?$`), "test file"},
+	{regexp.MustCompile(`\.(log|tgz)$`), "log file or tarball"},
+}
+
+func packFileWarning(relPath string, size int64) string {
+	for _, pattern := range suspiciousFilePatterns {
+		if pattern.regex.MatchString(relPath) {
+			return pattern.warning
+		}
+	}
+	if size > LargePackFileSize {
+		return "large file"
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPackList(t *testing.T) {
+	type scenario struct {
+		name             string
+		files            map[string]string
+		pkgConfig        PackageConfig
+		expectedPaths    []string
+		expectedWarnings map[string]string
+	}
+
+	scenarios := []scenario{
+		{
+			name: "npmignore takes precedence over gitignore",
+			files: map[string]string{
+				"package.json":        "{}",
+				"README.md":           "readme",
+				"index.js":            "",
+				".npmignore":          "test/\n*.log\n",
+				".gitignore":          "dist\n",
+				"dist/index.js":       "",
+				"test/index.test.js":  "",
+				"debug.log":           "",
+				"node_modules/a/a.js": "",
+				".git/HEAD":           "",
+				"package-lock.json":   "",
+				".env":                "SECRET=1",
+				"lib/.gitignore":      "*.map\n!keep.map\n",
+				"lib/a.js":            "",
+				"lib/a.js.map":        "",
+				"lib/keep.map":        "",
+			},
+			expectedPaths: []string{".env", "README.md", "dist/index.js", "index.js", "lib/a.js", "lib/keep.map", "package.json"},
+			expectedWarnings: map[string]string{
+				".env": "environment file, may contain secrets",
+			},
+		},
+		{
+			name: "falls back to gitignore",
+			files: map[string]string{
+				"package.json":  "{}",
+				"index.js":      "",
+				".gitignore":    "/dist\ncoverage/\n",
+				"dist/index.js": "",
+				"src/dist/a.js": "",
+				"coverage/x":    "",
+			},
+			expectedPaths: []string{"index.js", "package.json", "src/dist/a.js"},
+		},
+		{
+			name: "files field",
+			files: map[string]string{
+				"package.json":              "{}",
+				"LICENSE":                   "",
+				"CHANGELOG.md":              "",
+				"main.js":                   "",
+				"other.js":                  "",
+				".npmignore":                "lib/\n",
+				"lib/index.js":              "",
+				"lib/__fixtures__/big.json": strings.Repeat("x", LargePackFileSize+1),
+				"lib/.npmignore":            "*.md\n",
+				"lib/notes.md":              "",
+				"lib/sub/other.js":          "",
+				"lib/sub/private.js":        "",
+				"types/index.d.ts":          "",
+				"types/index.ts":            "",
+				"bin/cli.js":                "",
+				"bin/helper.js":             "",
+			},
+			pkgConfig: PackageConfig{
+				Main:  "./main.js",
+				Bin:   map[string]string{"cli": "./bin/cli.js"},
+				Files: []string{"lib", "types/*.d.ts", "!lib/sub/private.js", "other.js/"},
+			},
+			expectedPaths: []string{"CHANGELOG.md", "LICENSE", "bin/cli.js", "lib/__fixtures__/big.json", "lib/index.js", "lib/sub/other.js", "main.js", "package.json", "types/index.d.ts"},
+			expectedWarnings: map[string]string{
+				"lib/__fixtures__/big.json": "test fixtures or coverage output",
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lazynpm")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			for path, content := range s.files {
+				path = filepath.Join(dir, filepath.FromSlash(path))
+				assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+			}
+
+			list, err := NewDummyNpmManager().GetPackList(&Package{Path: dir, Config: s.pkgConfig})
+			assert.NoError(t, err)
+
+			paths := []string{}
+			warnings := map[string]string{}
+			var totalSize int64
+			for _, file := range list.Files {
+				paths = append(paths, file.Path)
+				if file.Warning != "" {
+					warnings[file.Path] = file.Warning
+				}
+				totalSize += int64(len(s.files[file.Path]))
+			}
+			assert.EqualValues(t, s.expectedPaths, paths)
+			if s.expectedWarnings == nil {
+				s.expectedWarnings = map[string]string{}
+			}
+			assert.EqualValues(t, s.expectedWarnings, warnings)
+			assert.EqualValues(t, totalSize, list.TotalSize)
+		})
+	}
+}
+
+func TestPackFileWarning(t *testing.T) {
+	assert.EqualValues(t, "", packFileWarning("lib/index.js", 100))
+	assert.EqualValues(t, "large file", packFileWarning("lib/index.js", LargePackFileSize+1))
+	assert.EqualValues(t, "environment file, may contain secrets", packFileWarning("config/.env.production", 10))
+	assert.EqualValues(t, "looks like a private key", packFileWarning("certs/server.pem", 10))
+	assert.EqualValues(t, "test file", packFileWarning("src/thing.spec.ts", 10))
+}
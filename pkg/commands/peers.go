@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// PeerIssue is a peer dependency of an installed package that either can't be
+// found from where the package is installed, or is installed at a version the
+// package doesn't support
+type PeerIssue struct {
+	// Name and Version are of the package requiring the peer
+	Name    string
+	Version string
+	// Path is the requiring package's directory, relative to the root package
+	Path       string
+	Peer       string
+	Constraint string
+	// Installed is the version the package would get when requiring the peer,
+	// or empty if the peer can't be found
+	Installed string
+	// InstalledPath is relative to the root package
+	InstalledPath string
+	Optional      bool
+}
+
+// Unmet is true if the peer is missing altogether, as opposed to being
+// installed at the wrong version
+func (p *PeerIssue) Unmet() bool {
+	return p.Installed == ""
+}
+
+// GetPeerIssues checks the peer dependencies of everything installed in the
+// package's node_modules against what each package would get if it required
+// them. Optional peers only need checking if they're installed.
+func (m *NpmManager) GetPeerIssues(pkg *Package, index *DependentsIndex) []*PeerIssue {
+	relPath := func(path string) string {
+		if rel, err := filepath.Rel(pkg.Path, path); err == nil {
+			return rel
+		}
+		return path
+	}
+
+	issues := []*PeerIssue{}
+	for path, pkgConfig := range index.packages {
+		fromDir := path
+		if linkPath, err := filepath.EvalSymlinks(path); err == nil {
+			fromDir = linkPath
+		}
+		for peer, constraint := range pkgConfig.PeerDependencies {
+			issue := &PeerIssue{
+				Name:       pkgConfig.Name,
+				Version:    pkgConfig.Version,
+				Path:       relPath(path),
+				Peer:       peer,
+				Constraint: constraint,
+				Optional:   pkgConfig.PeerDependenciesMeta[peer].Optional,
+			}
+
+			target := ResolveDep(fromDir, peer)
+			if target == "" {
+				if !issue.Optional {
+					issues = append(issues, issue)
+				}
+				continue
+			}
+
+			// the peer may be installed somewhere we haven't indexed, like a
+			// node_modules folder above the root package
+			targetConfig := index.packages[target]
+			if targetConfig == nil {
+				var err error
+				if targetConfig, err = m.getPackageConfig(target); err != nil {
+					continue
+				}
+			}
+			if satisfiesConstraint(constraint, targetConfig.Version) {
+				continue
+			}
+			issue.Installed = targetConfig.Version
+			issue.InstalledPath = relPath(target)
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Peer < b.Peer
+	})
+	return issues
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPeerIssues(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	writePackageTree(t, rootPath, map[string]string{
+		"node_modules/react":                     `{"name": "react", "version": "16.0.0"}`,
+		"node_modules/aliased":                   `{"name": "other", "version": "1.0.0"}`,
+		"node_modules/react-dom":                 `{"name": "react-dom", "version": "17.0.0", "peerDependencies": {"react": "^17.0.0"}}`,
+		"node_modules/plugin":                    `{"name": "plugin", "version": "1.0.0", "peerDependencies": {"host": "^1.0.0", "react": "^17.0.0"}}`,
+		"node_modules/plugin/node_modules/react": `{"name": "react", "version": "17.0.2"}`,
+		"node_modules/lib":                       `{"name": "lib", "version": "1.0.0", "peerDependencies": {"maybe": "^1.0.0", "react": "^15.0.0", "aliased": "npm:other@1"}, "peerDependenciesMeta": {"maybe": {"optional": true}, "react": {"optional": true}}}`,
+	})
+
+	pkg := &Package{
+		Path:   rootPath,
+		Config: PackageConfig{Dependencies: map[string]string{"react": "^16.0.0", "react-dom": "^17.0.0", "plugin": "^1.0.0", "lib": "^1.0.0"}},
+	}
+
+	type issue struct {
+		name      string
+		peer      string
+		installed string
+		optional  bool
+	}
+	issues := []issue{}
+	manager := NewDummyNpmManager()
+	peerIssues := manager.GetPeerIssues(pkg, manager.BuildDependentsIndex(pkg))
+	for _, peerIssue := range peerIssues {
+		issues = append(issues, issue{name: peerIssue.Name, peer: peerIssue.Peer, installed: peerIssue.Installed, optional: peerIssue.Optional})
+	}
+
+	// an optional peer that's missing is fine, but if it's installed it still
+	// has to match. Unparseable constraints get the benefit of the doubt
+	assert.EqualValues(t, []issue{
+		{name: "lib", peer: "react", installed: "16.0.0", optional: true},
+		{name: "plugin", peer: "host"},
+		{name: "react-dom", peer: "react", installed: "16.0.0"},
+	}, issues)
+	assert.True(t, peerIssues[1].Unmet())
+	assert.EqualValues(t, filepath.Join("node_modules", "react-dom"), peerIssues[2].Path)
+	assert.EqualValues(t, filepath.Join("node_modules", "react"), peerIssues[2].InstalledPath)
+}
@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/go-errors/errors"
+	"github.com/jesseduffield/semver/v3"
+)
+
+// DefaultRegistryURL is where packages come from unless npm is configured otherwise
+const DefaultRegistryURL = "https://registry.npmjs.org/"
+
+// RegistryPackage is what the registry knows about a package
+type RegistryPackage struct {
+	Name        string
+	Description string
+	// DistTags maps tags like 'latest' and 'next' to versions
+	DistTags    map[string]string
+	Maintainers []string
+	Created     time.Time
+	Modified    time.Time
+	// Versions are sorted newest first
+	Versions []*RegistryVersion
+}
+
+// RegistryVersion is a published version of a package
+type RegistryVersion struct {
+	Version string
+	// Published is zero if the registry didn't tell us
+	Published time.Time
+	// Deprecated is the deprecation message, if the version is deprecated
+	Deprecated string
+}
+
+// TagsForVersion returns the dist-tags pointing at the given version
+func (p *RegistryPackage) TagsForVersion(version string) []string {
+	tags := []string{}
+	for tag, taggedVersion := range p.DistTags {
+		if taggedVersion == version {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Version returns nil if the version hasn't been published
+func (p *RegistryPackage) Version(version string) *RegistryVersion {
+	for _, registryVersion := range p.Versions {
+		if registryVersion.Version == version {
+			return registryVersion
+		}
+	}
+	return nil
+}
+
+// RegistryClient talks to an npm registry over http
+type RegistryClient struct {
+	URL string
+	// Authorization is sent as the Authorization header, unless it's empty
+	Authorization string
+	HTTPClient    *http.Client
+}
+
+func NewRegistryClient(registryURL string, authorization string) *RegistryClient {
+	return &RegistryClient{
+		URL:           registryURL,
+		Authorization: authorization,
+		HTTPClient:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// registryClientFor returns a client for the registry the named package lives
+// in according to the npm config, using whatever credentials it has for it
+func registryClientFor(npmConfig *NpmConfig, name string) *RegistryClient {
+	registryURL := npmConfig.RegistryFor(name)
+	return NewRegistryClient(registryURL, npmConfig.AuthorizationFor(registryURL))
+}
+
+// GetPackage fetches the package's full metadata document
+func (c *RegistryClient) GetPackage(name string) (*RegistryPackage, error) {
+	// the abbreviated format you get by default from some registries leaves
+	// out publish times and maintainers
+	return c.getPackage(name, "application/json")
+}
+
+// GetAbbreviatedPackage fetches the much smaller document npm uses when
+// installing. It has the dist-tags and each version's deprecation message,
+// but no publish times, maintainers, or description
+func (c *RegistryClient) GetAbbreviatedPackage(name string) (*RegistryPackage, error) {
+	return c.getPackage(name, "application/vnd.npm.install-v1+json; q=1.0, application/json; q=0.8")
+}
+
+func (c *RegistryClient) getPackage(name string, accept string) (*RegistryPackage, error) {
+	// scoped packages need their slash escaped e.g. '@scope%2Fname'
+	packageURL := strings.TrimSuffix(c.URL, "/") + "/" + url.PathEscape(name)
+
+	req, err := http.NewRequest("GET", packageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if c.Authorization != "" {
+		req.Header.Set("Authorization", c.Authorization)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errors.New(name + " not found in registry " + c.URL)
+	case resp.StatusCode != http.StatusOK:
+		return nil, errors.Errorf("registry %s responded with %s", c.URL, resp.Status)
+	}
+
+	return ParseRegistryPackage(body)
+}
+
+// ParseRegistryPackage parses a package document as served by the registry
+func ParseRegistryPackage(data []byte) (*RegistryPackage, error) {
+	pkg := &RegistryPackage{DistTags: map[string]string{}, Maintainers: []string{}, Versions: []*RegistryVersion{}}
+
+	name, err := jsonparser.GetString(data, "name")
+	if err != nil {
+		return nil, errors.New("could not parse package from registry")
+	}
+	pkg.Name = name
+	pkg.Description, _ = jsonparser.GetString(data, "description")
+
+	_ = jsonparser.ObjectEach(data, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		if dataType == jsonparser.String {
+			pkg.DistTags[unescape(key)] = unescape(value)
+		}
+		return nil
+	}, "dist-tags")
+
+	_, _ = jsonparser.ArrayEach(data, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
+		maintainerName, _ := jsonparser.GetString(value, "name")
+		email, _ := jsonparser.GetString(value, "email")
+		switch {
+		case dataType == jsonparser.String:
+			pkg.Maintainers = append(pkg.Maintainers, unescape(value))
+		case email != "":
+			pkg.Maintainers = append(pkg.Maintainers, maintainerName+" <"+email+">")
+		case maintainerName != "":
+			pkg.Maintainers = append(pkg.Maintainers, maintainerName)
+		}
+	}, "maintainers")
+
+	times := map[string]time.Time{}
+	_ = jsonparser.ObjectEach(data, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		if parsed, err := time.Parse(time.RFC3339, string(value)); err == nil {
+			times[unescape(key)] = parsed
+		}
+		return nil
+	}, "time")
+	pkg.Created = times["created"]
+	pkg.Modified = times["modified"]
+
+	_ = jsonparser.ObjectEach(data, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+		version := unescape(key)
+		registryVersion := &RegistryVersion{Version: version, Published: times[version]}
+		registryVersion.Deprecated, _ = jsonparser.GetString(value, "deprecated")
+		pkg.Versions = append(pkg.Versions, registryVersion)
+		return nil
+	}, "versions")
+
+	sort.SliceStable(pkg.Versions, func(i, j int) bool {
+		a, errA := semver.NewVersion(pkg.Versions[i].Version)
+		b, errB := semver.NewVersion(pkg.Versions[j].Version)
+		if errA != nil || errB != nil {
+			return pkg.Versions[i].Published.After(pkg.Versions[j].Published)
+		}
+		return a.GreaterThan(b)
+	})
+
+	return pkg, nil
+}
+
+// GetRegistryPackage fetches the named package from whichever registry the
+// given package's npm config says it lives in
+func (m *NpmManager) GetRegistryPackage(pkg *Package, name string) (*RegistryPackage, error) {
+	return registryClientFor(m.GetNpmConfig(pkg), name).GetPackage(name)
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPackument = `{
+  "name": "@scope/pkg",
+  "description": "A package",
+  "dist-tags": {"latest": "1.10.0", "next": "2.0.0-beta.1"},
+  "maintainers": [{"name": "jesse", "email": "jesse@example.com"}, {"name": "bot"}],
+  "time": {
+    "created": "2019-01-01T00:00:00.000Z",
+    "modified": "2020-03-01T00:00:00.000Z",
+    "1.2.0": "2019-01-01T00:00:00.000Z",
+    "1.10.0": "2019-06-01T00:00:00.000Z",
+    "2.0.0-beta.1": "2020-03-01T00:00:00.000Z"
+  },
+  "versions": {
+    "1.2.0": {"name": "@scope/pkg", "version": "1.2.0", "deprecated": "please upgrade"},
+    "2.0.0-beta.1": {"name": "@scope/pkg", "version": "2.0.0-beta.1"},
+    "1.10.0": {"name": "@scope/pkg", "version": "1.10.0"}
+  }
+}`
+
+func TestRegistryClientGetPackage(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.EscapedPath())
+		if r.URL.EscapedPath() != "/@scope%2Fpkg" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.EqualValues(t, "application/json", r.Header.Get("Accept"))
+		assert.EqualValues(t, "Bearer secret", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(testPackument))
+	}))
+	defer server.Close()
+
+	client := NewRegistryClient(server.URL+"/", "Bearer secret")
+
+	pkg, err := client.GetPackage("@scope/pkg")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "@scope/pkg", pkg.Name)
+	assert.EqualValues(t, "A package", pkg.Description)
+	assert.EqualValues(t, map[string]string{"latest": "1.10.0", "next": "2.0.0-beta.1"}, pkg.DistTags)
+	assert.EqualValues(t, []string{"jesse <jesse@example.com>", "bot"}, pkg.Maintainers)
+	assert.EqualValues(t, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), pkg.Created)
+	assert.EqualValues(t, time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), pkg.Modified)
+
+	// sorted by semver, not lexically or by the order they appear in
+	versions := make([]string, len(pkg.Versions))
+	for i, version := range pkg.Versions {
+		versions[i] = version.Version
+	}
+	assert.EqualValues(t, []string{"2.0.0-beta.1", "1.10.0", "1.2.0"}, versions)
+	assert.EqualValues(t, "please upgrade", pkg.Version("1.2.0").Deprecated)
+	assert.EqualValues(t, time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC), pkg.Version("1.10.0").Published)
+	assert.Nil(t, pkg.Version("3.0.0"))
+	assert.EqualValues(t, []string{"latest"}, pkg.TagsForVersion("1.10.0"))
+
+	_, err = client.GetPackage("missing")
+	assert.EqualError(t, err, "missing not found in registry "+server.URL+"/")
+
+	assert.EqualValues(t, []string{"/@scope%2Fpkg", "/missing"}, requests)
+}
+
+func TestRegistryClientServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := NewRegistryClient(server.URL, "").GetPackage("pkg")
+	assert.EqualError(t, err, "registry "+server.URL+" responded with 500 Internal Server Error")
+}
@@ -1,12 +1,347 @@
 package commands
 
-import "fmt"
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+)
 
 type Tarball struct {
-	Name string
-	Path string
+	Name    string
+	Path    string
+	Size    int64
+	ModTime time.Time
+	// Shasum is the hex sha1 of the tarball, as shown by `npm pack`
+	Shasum string
+	// Integrity is the sha512 subresource integrity string that ends up in lockfiles
+	Integrity string
+	// Hashed is false until HashTarballs has worked out Shasum and Integrity
+	Hashed bool
+	// PackagePath is the path of the package the tarball was packed from, or
+	// empty if we can't tell
+	PackagePath string
+	PackageName string
+	// Stale is true when the package's package.json has changed since the
+	// tarball was packed
+	Stale bool
 }
 
 func (t *Tarball) ID() string {
 	return fmt.Sprintf("tarball:%s", t.Path)
 }
+
+// TarballFile is a file inside a tarball. Path is relative to the package
+// root i.e. without the leading 'package/'
+type TarballFile struct {
+	Path string
+	Size int64
+}
+
+// TarballContents is what would end up in node_modules if the tarball were installed
+type TarballContents struct {
+	Files        []TarballFile
+	UnpackedSize int64
+	// PackageConfig is nil if the tarball has no package.json at its root
+	PackageConfig *PackageConfig
+
+	// for knowing when our cached contents are stale
+	modTime time.Time
+	size    int64
+}
+
+// ReadTarball reads a gzipped tarball as produced by `npm pack`
+func ReadTarball(r io.Reader) (*TarballContents, error) {
+	contents := &TarballContents{Files: []TarballFile{}}
+	err := walkTarball(r, func(relPath string, size int64, fileReader io.Reader) error {
+		contents.Files = append(contents.Files, TarballFile{Path: relPath, Size: size})
+		contents.UnpackedSize += size
+
+		if relPath == "package.json" {
+			pkgConfig, err := UnmarshalPackageConfig(fileReader, nil)
+			if err != nil {
+				return errors.New("could not parse package.json in tarball: " + err.Error())
+			}
+			contents.PackageConfig = pkgConfig
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(contents.Files, func(i, j int) bool { return contents.Files[i].Path < contents.Files[j].Path })
+
+	return contents, nil
+}
+
+// walkTarball calls f for each regular file in a gzipped tarball. npm always
+// puts the package in a 'package' folder but some tarballs on the registry use
+// a different name, so we just strip whatever the top folder is.
+func walkTarball(r io.Reader, f func(relPath string, size int64, fileReader io.Reader) error) error {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		idx := strings.Index(name, "/")
+		if idx == -1 {
+			continue
+		}
+
+		if err := f(name[idx+1:], header.Size, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+// GetTarballContents reads the tarball, reusing what we read last time if the
+// file hasn't changed
+func (m *NpmManager) GetTarballContents(tarball *Tarball) (*TarballContents, error) {
+	fileInfo, err := os.Stat(tarball.Path)
+	if err != nil {
+		return nil, err
+	}
+	m.tarballContentsMutex.Lock()
+	cached := m.tarballContents[tarball.Path]
+	m.tarballContentsMutex.Unlock()
+	if cached != nil && cached.modTime.Equal(fileInfo.ModTime()) && cached.size == fileInfo.Size() {
+		return cached, nil
+	}
+
+	file, err := os.Open(tarball.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	contents, err := ReadTarball(file)
+	if err != nil {
+		return nil, err
+	}
+	contents.modTime = fileInfo.ModTime()
+	contents.size = fileInfo.Size()
+
+	m.tarballContentsMutex.Lock()
+	m.tarballContents[tarball.Path] = contents
+	m.tarballContentsMutex.Unlock()
+
+	return contents, nil
+}
+
+// GetTarballs finds the tarballs in each package's folder, along with the
+// folder configured as packDestination (relative to the current package).
+// Each tarball is matched up with the package it was packed from.
+func (m *NpmManager) GetTarballs(pkgs []*Package) ([]*Tarball, error) {
+	if len(pkgs) == 0 {
+		return []*Tarball{}, nil
+	}
+
+	dirs := []string{}
+	dirPackages := map[string]*Package{}
+	for _, pkg := range pkgs {
+		dir := filepath.Clean(pkg.Path)
+		if _, ok := dirPackages[dir]; !ok {
+			dirs = append(dirs, dir)
+			dirPackages[dir] = pkg
+		}
+	}
+	if packDestination := m.Config.GetUserConfig().GetString("packDestination"); packDestination != "" {
+		if !filepath.IsAbs(packDestination) {
+			packDestination = filepath.Join(pkgs[0].Path, packDestination)
+		}
+		dir := filepath.Clean(packDestination)
+		if _, ok := dirPackages[dir]; !ok {
+			dirs = append(dirs, dir)
+			dirPackages[dir] = nil
+		}
+	}
+
+	configModTimes := map[string]time.Time{}
+	for _, pkg := range pkgs {
+		if fileInfo, err := os.Stat(filepath.Join(pkg.Path, "package.json")); err == nil {
+			configModTimes[pkg.Path] = fileInfo.ModTime()
+		}
+	}
+
+	tarballs := []*Tarball{}
+	for _, dir := range dirs {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.tgz"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			tarball, err := m.getTarball(path)
+			if err != nil {
+				// the file may have been removed since we globbed
+				m.Log.Error(err)
+				continue
+			}
+
+			if pkg := tarballPackage(tarball.Name, pkgs, dirPackages[dir]); pkg != nil {
+				tarball.PackagePath = pkg.Path
+				tarball.PackageName = pkg.Config.Name
+				if configModTime, ok := configModTimes[pkg.Path]; ok {
+					tarball.Stale = tarball.ModTime.Before(configModTime)
+				}
+			}
+			tarballs = append(tarballs, tarball)
+		}
+	}
+
+	return tarballs, nil
+}
+
+// tarballHashes are what we remember about a tarball between refreshes, given
+// hashing a big tarball takes a while
+type tarballHashes struct {
+	shasum    string
+	integrity string
+
+	// for knowing when our cached hashes are stale
+	modTime time.Time
+	size    int64
+}
+
+// getTarball stats the tarball at the given path, filling in the hashes we
+// worked out last time if the file hasn't changed since
+func (m *NpmManager) getTarball(path string) (*Tarball, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tarball := &Tarball{
+		Name:    filepath.Base(path),
+		Path:    path,
+		Size:    fileInfo.Size(),
+		ModTime: fileInfo.ModTime(),
+	}
+	if hashes := m.cachedTarballHashes(path, fileInfo); hashes != nil {
+		tarball.Shasum = hashes.shasum
+		tarball.Integrity = hashes.integrity
+		tarball.Hashed = true
+	}
+	return tarball, nil
+}
+
+func (m *NpmManager) cachedTarballHashes(path string, fileInfo os.FileInfo) *tarballHashes {
+	m.tarballsMutex.Lock()
+	hashes := m.tarballs[path]
+	m.tarballsMutex.Unlock()
+	if hashes == nil || !hashes.modTime.Equal(fileInfo.ModTime()) || hashes.size != fileInfo.Size() {
+		return nil
+	}
+	return hashes
+}
+
+// HashTarballs works out the hashes of the given tarballs, so that GetTarballs
+// can fill them in from then on. This reads each tarball in full, so it's best
+// done in the background
+func (m *NpmManager) HashTarballs(tarballs []*Tarball) {
+	for _, tarball := range tarballs {
+		fileInfo, err := os.Stat(tarball.Path)
+		if err != nil {
+			// the file may have been removed in the meantime
+			continue
+		}
+		if m.cachedTarballHashes(tarball.Path, fileInfo) != nil {
+			continue
+		}
+
+		hashes, err := hashTarball(tarball.Path)
+		if err != nil {
+			// we remember the failure so that we don't keep trying until
+			// the file changes
+			m.Log.Error(err)
+			hashes = &tarballHashes{}
+		}
+		hashes.modTime = fileInfo.ModTime()
+		hashes.size = fileInfo.Size()
+
+		m.tarballsMutex.Lock()
+		m.tarballs[tarball.Path] = hashes
+		m.tarballsMutex.Unlock()
+	}
+}
+
+func hashTarball(path string) (*tarballHashes, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sha1Hash := sha1.New()
+	sha512Hash := sha512.New()
+	if _, err := io.Copy(io.MultiWriter(sha1Hash, sha512Hash), file); err != nil {
+		return nil, err
+	}
+
+	return &tarballHashes{
+		shasum:    hex.EncodeToString(sha1Hash.Sum(nil)),
+		integrity: "sha512-" + base64.StdEncoding.EncodeToString(sha512Hash.Sum(nil)),
+	}, nil
+}
+
+// tarballPackage works out which package a tarball came from by its filename:
+// npm names tarballs like 'scope-name-1.0.0.tgz' and yarn like
+// 'scope-name-v1.0.0.tgz'. If no package matches, we go with the package whose
+// folder the tarball is in.
+func tarballPackage(name string, pkgs []*Package, dirPkg *Package) *Package {
+	var match *Package
+	matchLength := 0
+	for _, pkg := range pkgs {
+		prefix := tarballPrefix(pkg.Config.Name)
+		if prefix == "" || !strings.HasPrefix(name, prefix) || len(prefix) <= matchLength {
+			continue
+		}
+		// making sure 'foo' doesn't claim 'foo-bar-1.0.0.tgz'
+		if rest := strings.TrimPrefix(name[len(prefix):], "v"); rest == "" || rest[0] < '0' || rest[0] > '9' {
+			continue
+		}
+		match = pkg
+		matchLength = len(prefix)
+	}
+	if match != nil {
+		return match
+	}
+	return dirPkg
+}
+
+// tarballPrefix is how a package's tarballs start, before the version
+func tarballPrefix(packageName string) string {
+	if packageName == "" {
+		return ""
+	}
+	return strings.Replace(strings.TrimPrefix(packageName, "@"), "/", "-", 1) + "-"
+}
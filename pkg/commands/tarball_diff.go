@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// maxDiffFileSize is the size above which we don't bother showing a diff of a file
+const maxDiffFileSize = 1024 * 1024
+
+// FileDiff is a file which differs between two versions of a package
+type FileDiff struct {
+	Path string
+	// Status is one of 'added', 'removed', or 'changed'
+	Status  string
+	OldSize int64
+	NewSize int64
+	// Diff is a unified diff. It's empty for added and removed files, and for
+	// binary or very large files
+	Diff   string
+	Binary bool
+}
+
+// TarballDiff describes how a package differs from one tarball (or directory)
+// to the next
+type TarballDiff struct {
+	OldName string
+	NewName string
+	// Files only includes files that differ, sorted by path
+	Files []*FileDiff
+	// Unchanged is the number of files that are the same in both
+	Unchanged int
+}
+
+// DiffFileSets compares two sets of file contents keyed by path
+func DiffFileSets(oldName string, oldFiles map[string][]byte, newName string, newFiles map[string][]byte) *TarballDiff {
+	diff := &TarballDiff{OldName: oldName, NewName: newName, Files: []*FileDiff{}}
+
+	for path, oldContent := range oldFiles {
+		newContent, ok := newFiles[path]
+		if !ok {
+			diff.Files = append(diff.Files, &FileDiff{Path: path, Status: "removed", OldSize: int64(len(oldContent))})
+			continue
+		}
+		if bytes.Equal(oldContent, newContent) {
+			diff.Unchanged++
+			continue
+		}
+
+		fileDiff := &FileDiff{Path: path, Status: "changed", OldSize: int64(len(oldContent)), NewSize: int64(len(newContent))}
+		if isBinary(oldContent) || isBinary(newContent) {
+			fileDiff.Binary = true
+		} else if len(oldContent) <= maxDiffFileSize && len(newContent) <= maxDiffFileSize {
+			fileDiff.Diff, _ = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        splitDiffLines(oldContent),
+				B:        splitDiffLines(newContent),
+				FromFile: oldName + "/" + path,
+				ToFile:   newName + "/" + path,
+				Context:  3,
+			})
+		}
+		diff.Files = append(diff.Files, fileDiff)
+	}
+
+	for path, newContent := range newFiles {
+		if _, ok := oldFiles[path]; !ok {
+			diff.Files = append(diff.Files, &FileDiff{Path: path, Status: "added", NewSize: int64(len(newContent))})
+		}
+	}
+
+	sort.Slice(diff.Files, func(i, j int) bool { return diff.Files[i].Path < diff.Files[j].Path })
+	return diff
+}
+
+// splitDiffLines keeps the newline on each line. Unlike difflib.SplitLines it
+// doesn't add a blank line to the end
+func splitDiffLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// isBinary uses the same heuristic as git: text files don't contain null bytes
+func isBinary(content []byte) bool {
+	sample := content
+	if len(sample) > 8000 {
+		sample = sample[:8000]
+	}
+	return bytes.IndexByte(sample, 0) != -1
+}
+
+// DiffTarballs compares the contents of two tarballs
+func (m *NpmManager) DiffTarballs(oldTarball *Tarball, newTarball *Tarball) (*TarballDiff, error) {
+	oldFiles, err := readTarballFiles(oldTarball.Path)
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := readTarballFiles(newTarball.Path)
+	if err != nil {
+		return nil, err
+	}
+	return DiffFileSets(oldTarball.Name, oldFiles, newTarball.Name, newFiles), nil
+}
+
+// DiffTarballWithPackage compares a tarball with what we'd get if we packed
+// the package right now
+func (m *NpmManager) DiffTarballWithPackage(tarball *Tarball, pkg *Package) (*TarballDiff, error) {
+	tarballFiles, err := readTarballFiles(tarball.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := m.GetPackList(pkg)
+	if err != nil {
+		return nil, err
+	}
+	pkgFiles := make(map[string][]byte, len(list.Files))
+	for _, file := range list.Files {
+		content, err := ioutil.ReadFile(filepath.Join(pkg.Path, filepath.FromSlash(file.Path)))
+		if err != nil {
+			return nil, err
+		}
+		pkgFiles[file.Path] = content
+	}
+
+	return DiffFileSets(tarball.Name, tarballFiles, pkg.Config.Name, pkgFiles), nil
+}
+
+func readTarballFiles(path string) (map[string][]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	files := map[string][]byte{}
+	err = walkTarball(file, func(relPath string, size int64, fileReader io.Reader) error {
+		content, err := ioutil.ReadAll(fileReader)
+		if err != nil {
+			return err
+		}
+		files[relPath] = content
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffFileSets(t *testing.T) {
+	oldFiles := map[string][]byte{
+		"package.json": []byte("{\n  \"name\": \"a\",\n  \"version\": \"1.0.0\"\n}\n"),
+		"index.js":     []byte("module.exports = 1\n"),
+		"removed.js":   []byte("gone\n"),
+		"image.png":    {0x89, 0x50, 0x00, 0x01},
+	}
+	newFiles := map[string][]byte{
+		"package.json": []byte("{\n  \"name\": \"a\",\n  \"version\": \"1.0.1\"\n}\n"),
+		"index.js":     []byte("module.exports = 1\n"),
+		"added.js":     []byte("new\n"),
+		"image.png":    {0x89, 0x50, 0x00, 0x02},
+	}
+
+	diff := DiffFileSets("a-1.0.0.tgz", oldFiles, "a-1.0.1.tgz", newFiles)
+
+	assert.EqualValues(t, 1, diff.Unchanged)
+	assert.EqualValues(t, 4, len(diff.Files))
+
+	assert.EqualValues(t, &FileDiff{Path: "added.js", Status: "added", NewSize: 4}, diff.Files[0])
+	assert.EqualValues(t, &FileDiff{Path: "image.png", Status: "changed", OldSize: 4, NewSize: 4, Binary: true}, diff.Files[1])
+	assert.EqualValues(t, "package.json", diff.Files[2].Path)
+	assert.EqualValues(t, "changed", diff.Files[2].Status)
+	assert.EqualValues(t, `--- a-1.0.0.tgz/package.json
++++ a-1.0.1.tgz/package.json
+@@ -1,4 +1,4 @@
+ {
+   "name": "a",
+-  "version": "1.0.0"
++  "version": "1.0.1"
+ }
+`, diff.Files[2].Diff)
+	assert.EqualValues(t, &FileDiff{Path: "removed.js", Status: "removed", OldSize: 5}, diff.Files[3])
+}
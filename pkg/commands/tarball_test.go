@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadTarball(t *testing.T) {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, entry := range []struct {
+		name    string
+		content string
+		dir     bool
+	}{
+		{name: "package/", dir: true},
+		{name: "package/package.json", content: `{"name": "my-package", "version": "1.2.3", "dependencies": {"lodash": "^4.17.15"}}`},
+		{name: "package/lib/", dir: true},
+		{name: "package/lib/index.js", content: "module.exports = {}\n"},
+		{name: "package/README.md", content: "# my-package\n"},
+	} {
+		header := &tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(entry.content)), Typeflag: tar.TypeReg}
+		if entry.dir {
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0755
+		}
+		assert.NoError(t, tarWriter.WriteHeader(header))
+		_, err := tarWriter.Write([]byte(entry.content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+
+	contents, err := ReadTarball(buf)
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, []TarballFile{
+		{Path: "README.md", Size: 13},
+		{Path: "lib/index.js", Size: 20},
+		{Path: "package.json", Size: 82},
+	}, contents.Files)
+	assert.EqualValues(t, 115, contents.UnpackedSize)
+	assert.EqualValues(t, "my-package", contents.PackageConfig.Name)
+	assert.EqualValues(t, "1.2.3", contents.PackageConfig.Version)
+	assert.EqualValues(t, map[string]string{"lodash": "^4.17.15"}, contents.PackageConfig.Dependencies)
+
+	_, err = ReadTarball(bytes.NewBufferString("not a tarball"))
+	assert.Error(t, err)
+}
+
+func TestGetTarballs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	appDir := filepath.Join(dir, "app")
+	libDir := filepath.Join(dir, "lib")
+	packDir := filepath.Join(dir, "dist")
+	for path, content := range map[string]string{
+		filepath.Join(appDir, "package.json"):                `{"name": "app"}`,
+		filepath.Join(appDir, "app-1.0.0.tgz"):               "app tarball",
+		filepath.Join(appDir, "scope-lib-2.0.0.tgz"):         "lib tarball packed from app",
+		filepath.Join(libDir, "package.json"):                `{"name": "@scope/lib"}`,
+		filepath.Join(libDir, "random.tgz"):                  "unrecognised tarball",
+		filepath.Join(packDir, "app-extra-v1.0.0.tgz"):       "yarn tarball of another package",
+		filepath.Join(packDir, "scope-lib-2.1.0-beta.0.tgz"): "prerelease",
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	// the app's package.json has changed since its tarball was packed
+	past := time.Now().Add(-time.Hour)
+	for _, path := range []string{filepath.Join(libDir, "package.json"), filepath.Join(appDir, "app-1.0.0.tgz")} {
+		assert.NoError(t, os.Chtimes(path, past, past))
+	}
+
+	pkgs := []*Package{
+		{Path: appDir, Config: PackageConfig{Name: "app"}},
+		{Path: libDir, Config: PackageConfig{Name: "@scope/lib"}},
+	}
+	npmManager := NewDummyNpmManager()
+	npmManager.Config.GetUserConfig().Set("packDestination", "../dist")
+
+	tarballs, err := npmManager.GetTarballs(pkgs)
+	assert.NoError(t, err)
+
+	type result struct {
+		name        string
+		packagePath string
+		stale       bool
+	}
+	results := make([]result, len(tarballs))
+	for i, tarball := range tarballs {
+		results[i] = result{name: tarball.Name, packagePath: tarball.PackagePath, stale: tarball.Stale}
+	}
+	assert.EqualValues(t, []result{
+		{name: "app-1.0.0.tgz", packagePath: appDir, stale: true},
+		{name: "scope-lib-2.0.0.tgz", packagePath: libDir},
+		{name: "random.tgz", packagePath: libDir},
+		{name: "app-extra-v1.0.0.tgz"},
+		{name: "scope-lib-2.1.0-beta.0.tgz", packagePath: libDir},
+	}, results)
+
+	assert.EqualValues(t, 11, tarballs[0].Size)
+	assert.EqualValues(t, "app", tarballs[0].PackageName)
+
+	// hashing is left until it's asked for
+	assert.False(t, tarballs[0].Hashed)
+	assert.EqualValues(t, "", tarballs[0].Shasum)
+	npmManager.HashTarballs(tarballs)
+	tarballs, err = npmManager.GetTarballs(pkgs)
+	assert.NoError(t, err)
+	assert.True(t, tarballs[0].Hashed)
+	// echo -n 'app tarball' | shasum
+	assert.EqualValues(t, "55d573e2a7baf53dd1e9412859aaa01c1406e95c", tarballs[0].Shasum)
+	assert.EqualValues(t, "sha512-LuOB35YiLGX/9s+4iP+QZGTpg32FsSaLXTi/MiyTdRvz/ax7qldmXsqaW0c1Bb/XqIo0279NmBM5mjMBDZ2cPg==", tarballs[0].Integrity)
+
+	// looking again after the app is renamed reuses the hashes without
+	// touching the tarballs we've already handed out
+	renamedPkgs := []*Package{{Path: appDir, Config: PackageConfig{Name: "renamed"}}, pkgs[1]}
+	renamedTarballs, err := npmManager.GetTarballs(renamedPkgs)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "renamed", renamedTarballs[0].PackageName)
+	assert.EqualValues(t, "app", tarballs[0].PackageName)
+	assert.EqualValues(t, tarballs[0].Shasum, renamedTarballs[0].Shasum)
+}
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/jesseduffield/semver/v3"
+)
+
+// VersionIncrements are the kinds of version bump we offer, mirroring `npm version`
+func VersionIncrements() []string {
+	return []string{"patch", "minor", "major", "prepatch", "preminor", "premajor", "prerelease"}
+}
+
+// IsPrereleaseIncrement tells us whether the increment takes a preid e.g. 'beta'
+func IsPrereleaseIncrement(increment string) bool {
+	return strings.HasPrefix(increment, "pre")
+}
+
+// CurrentPreid returns the identifier of the version's prerelease e.g. 'beta'
+// for 1.0.0-beta.2, so that by default we keep using it. Empty if there isn't one
+func CurrentPreid(current string) string {
+	version, err := semver.StrictNewVersion(current)
+	if err != nil {
+		return ""
+	}
+	preid := strings.Split(version.Prerelease(), ".")[0]
+	if _, err := strconv.ParseUint(preid, 10, 64); err == nil {
+		return ""
+	}
+	return preid
+}
+
+// BumpVersion works out what the version would be after the given increment,
+// following npm. Bumping a prerelease to the version it's a prerelease of just
+// drops the prerelease e.g. a minor of 1.1.0-beta.0 is 1.1.0, whereas a minor
+// of 1.1.1-beta.0 is 1.2.0. A prerelease of 1.0.0 is 1.0.1-0 (or 1.0.1-beta.0
+// given a preid of 'beta') and 1.0.1-beta.0 becomes 1.0.1-beta.1. The other
+// pre* increments always bump their part of the version and start the count
+// again e.g. a preminor of 1.0.1-beta.0 is 1.1.0-beta.0
+func BumpVersion(current string, increment string, preid string) (string, error) {
+	version, err := semver.StrictNewVersion(current)
+	if err != nil {
+		return "", errors.New("current version is not valid semver: " + current)
+	}
+
+	var bumped semver.Version
+	switch increment {
+	case "patch":
+		bumped = version.IncPatch()
+	case "minor":
+		// like IncPatch, a prerelease of the next minor version is released as is
+		if version.Prerelease() != "" && version.Patch() == 0 {
+			bumped, err = version.SetPrerelease("")
+		} else {
+			bumped = version.IncMinor()
+		}
+	case "major":
+		if version.Prerelease() != "" && version.Minor() == 0 && version.Patch() == 0 {
+			bumped, err = version.SetPrerelease("")
+		} else {
+			bumped = version.IncMajor()
+		}
+	case "prepatch":
+		// IncPatch just drops the prerelease of a version that has one
+		release, _ := version.SetPrerelease("")
+		bumped, err = release.IncPatch().SetPrerelease(joinPrerelease(preid, "0"))
+	case "preminor":
+		bumped, err = version.IncMinor().SetPrerelease(joinPrerelease(preid, "0"))
+	case "premajor":
+		bumped, err = version.IncMajor().SetPrerelease(joinPrerelease(preid, "0"))
+	case "prerelease":
+		bumped, err = bumpPrerelease(version, preid)
+	default:
+		return "", errors.New("unknown version increment: " + increment)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return bumped.String(), nil
+}
+
+func bumpPrerelease(version *semver.Version, preid string) (semver.Version, error) {
+	prerelease := version.Prerelease()
+	if prerelease == "" {
+		base := version.IncPatch()
+		return base.SetPrerelease(joinPrerelease(preid, "0"))
+	}
+
+	parts := strings.Split(prerelease, ".")
+	last := parts[len(parts)-1]
+	if preid != "" && parts[0] != preid {
+		// switching to a different preid starts the count again
+		return version.SetPrerelease(joinPrerelease(preid, "0"))
+	}
+	if n, err := strconv.ParseUint(last, 10, 64); err == nil {
+		parts[len(parts)-1] = strconv.FormatUint(n+1, 10)
+	} else {
+		parts = append(parts, "0")
+	}
+	return version.SetPrerelease(strings.Join(parts, "."))
+}
+
+func joinPrerelease(preid string, n string) string {
+	if preid == "" {
+		return n
+	}
+	return preid + "." + n
+}
+
+// ValidateVersion makes sure a version typed in by the user is strict semver,
+// allowing for a leading 'v' as npm does
+func ValidateVersion(version string) (string, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	parsed, err := semver.StrictNewVersion(version)
+	if err != nil {
+		return "", errors.New(version + " is not a valid semver version")
+	}
+	return parsed.String(), nil
+}
+
+// VersionFiles returns the files that bumping the package's version would
+// touch, relative to the package's directory
+func (m *NpmManager) VersionFiles(pkg *Package) []string {
+	files := []string{"package.json"}
+	if lockfilePath := versionLockfilePath(pkg); lockfilePath != "" {
+		relPath, err := filepath.Rel(pkg.Path, lockfilePath)
+		if err != nil {
+			relPath = lockfilePath
+		}
+		files = append(files, relPath)
+	}
+	return files
+}
+
+// WriteVersion sets the package's version in its package.json and lockfile
+// without going through the package manager, which means no git commit or tag
+func (m *NpmManager) WriteVersion(pkg *Package, version string) error {
+	if err := m.EditPackageJSON(pkg.ConfigPath(), func(packageJSON *PackageJSON) error {
+		return packageJSON.SetString(version, "version")
+	}); err != nil {
+		return err
+	}
+
+	lockfilePath := versionLockfilePath(pkg)
+	if lockfilePath == "" {
+		return nil
+	}
+
+	return m.EditPackageJSON(lockfilePath, func(lockfile *PackageJSON) error {
+		if !pkg.IsWorkspaceMember() && lockfile.Has("version") {
+			if err := lockfile.SetString(version, "version"); err != nil {
+				return err
+			}
+		}
+		// v2+ lockfiles also have an entry for each workspace package, keyed by path
+		key := ""
+		if pkg.IsWorkspaceMember() {
+			relPath, err := filepath.Rel(pkg.WorkspaceRootPath, pkg.Path)
+			if err != nil {
+				return err
+			}
+			key = filepath.ToSlash(relPath)
+		}
+		if lockfile.Has("packages", key, "version") {
+			return lockfile.SetString(version, "packages", key, "version")
+		}
+		return nil
+	})
+}
+
+// versionLockfilePath returns the npm lockfile holding the package's version, if any
+func versionLockfilePath(pkg *Package) string {
+	dir := pkg.Path
+	if pkg.IsWorkspaceMember() {
+		dir = pkg.WorkspaceRootPath
+	}
+	for _, name := range LockfileNames() {
+		path := filepath.Join(dir, name)
+		if FileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBumpVersion(t *testing.T) {
+	type scenario struct {
+		current   string
+		increment string
+		preid     string
+		expected  string
+		expectErr bool
+	}
+
+	scenarios := []scenario{
+		{"1.2.3", "patch", "", "1.2.4", false},
+		{"1.2.3", "minor", "", "1.3.0", false},
+		{"1.2.3", "major", "", "2.0.0", false},
+		{"1.2.3-beta.1", "patch", "", "1.2.3", false},
+		{"1.1.0-beta.0", "minor", "", "1.1.0", false},
+		{"1.1.1-beta.0", "minor", "", "1.2.0", false},
+		{"2.0.0-rc.1", "major", "", "2.0.0", false},
+		{"2.1.0-rc.1", "major", "", "3.0.0", false},
+		{"2.0.1-rc.1", "major", "", "3.0.0", false},
+		{"1.2.3", "prerelease", "", "1.2.4-0", false},
+		{"1.2.3", "prerelease", "beta", "1.2.4-beta.0", false},
+		{"1.2.4-beta.0", "prerelease", "beta", "1.2.4-beta.1", false},
+		{"1.2.4-beta.0", "prerelease", "", "1.2.4-beta.1", false},
+		{"1.2.4-alpha.3", "prerelease", "beta", "1.2.4-beta.0", false},
+		{"1.2.4-rc", "prerelease", "", "1.2.4-rc.0", false},
+		{"1.2.3", "prepatch", "", "1.2.4-0", false},
+		{"1.2.3", "preminor", "beta", "1.3.0-beta.0", false},
+		{"1.2.3", "premajor", "rc", "2.0.0-rc.0", false},
+		{"1.2.4-beta.0", "prepatch", "beta", "1.2.5-beta.0", false},
+		{"1.2.3", "prerelease", "not valid!", "", true},
+		{"1.2", "patch", "", "", true},
+		{"1.2.3", "sideways", "", "", true},
+	}
+
+	for _, s := range scenarios {
+		version, err := BumpVersion(s.current, s.increment, s.preid)
+		if s.expectErr {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.EqualValues(t, s.expected, version, s.current+" "+s.increment+" "+s.preid)
+	}
+}
+
+func TestCurrentPreid(t *testing.T) {
+	assert.EqualValues(t, "beta", CurrentPreid("1.0.0-beta.2"))
+	assert.EqualValues(t, "", CurrentPreid("1.0.0-2"))
+	assert.EqualValues(t, "", CurrentPreid("1.0.0"))
+	assert.EqualValues(t, "", CurrentPreid("not semver"))
+}
+
+func TestValidateVersion(t *testing.T) {
+	version, err := ValidateVersion(" v2.0.0-rc.1 ")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "2.0.0-rc.1", version)
+
+	for _, invalid := range []string{"", "2", "2.0", "latest", "2.0.0.0"} {
+		_, err := ValidateVersion(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestWriteVersion(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	memberPath := filepath.Join(rootPath, "packages", "member")
+	assert.NoError(t, os.MkdirAll(memberPath, 0755))
+	for path, content := range map[string]string{
+		"package.json":                 "{\n  \"name\": \"root\",\n  \"version\": \"1.0.0\"\n}\n",
+		"packages/member/package.json": "{\n  \"name\": \"member\",\n  \"version\": \"0.1.0\"\n}\n",
+		"package-lock.json": `{
+  "name": "root",
+  "version": "1.0.0",
+  "lockfileVersion": 2,
+  "packages": {
+    "": {
+      "name": "root",
+      "version": "1.0.0"
+    },
+    "packages/member": {
+      "name": "member",
+      "version": "0.1.0"
+    }
+  }
+}
+`,
+	} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(rootPath, path), []byte(content), 0644))
+	}
+
+	manager := NewDummyNpmManager()
+	root := &Package{Path: rootPath}
+	member := &Package{Path: memberPath, WorkspaceRootPath: rootPath}
+
+	assert.EqualValues(t, []string{"package.json", "package-lock.json"}, manager.VersionFiles(root))
+	assert.EqualValues(t, []string{"package.json", filepath.FromSlash("../../package-lock.json")}, manager.VersionFiles(member))
+
+	assert.NoError(t, manager.WriteVersion(root, "1.1.0"))
+	assert.NoError(t, manager.WriteVersion(member, "0.2.0"))
+
+	readFile := func(path string) string {
+		content, err := ioutil.ReadFile(filepath.Join(rootPath, path))
+		assert.NoError(t, err)
+		return string(content)
+	}
+
+	assert.EqualValues(t, "{\n  \"name\": \"root\",\n  \"version\": \"1.1.0\"\n}\n", readFile("package.json"))
+	assert.EqualValues(t, "{\n  \"name\": \"member\",\n  \"version\": \"0.2.0\"\n}\n", readFile("packages/member/package.json"))
+	assert.EqualValues(t, `{
+  "name": "root",
+  "version": "1.1.0",
+  "lockfileVersion": 2,
+  "packages": {
+    "": {
+      "name": "root",
+      "version": "1.1.0"
+    },
+    "packages/member": {
+      "name": "member",
+      "version": "0.2.0"
+    }
+  }
+}
+`, readFile("package-lock.json"))
+}
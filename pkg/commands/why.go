@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// max number of dependency chains we'll return for a package, given a big
+// dependency graph can have an absurd number of them
+const maxWhyChains = 100
+
+// DependentsIndex is a reverse index of node_modules, mapping each installed
+// package to the packages that depend on it
+type DependentsIndex struct {
+	rootPath string
+	// installed packages, keyed by directory
+	packages map[string]*PackageConfig
+	// maps a package's directory to the packages that resolve to it
+	dependents map[string][]dependent
+}
+
+type dependent struct {
+	// path is empty for the root package
+	path string
+	// kind is only set when the dependent is the root package
+	kind string
+}
+
+// WhyStep is a package in a chain of dependencies
+type WhyStep struct {
+	Name    string
+	Version string
+	Path    string
+}
+
+// WhyChain is a chain of dependencies leading from the root package to the
+// package we want to explain
+type WhyChain struct {
+	// Kind is the kind of dependency (prod/dev/optional/peer) the first step is
+	// of the root package
+	Kind  string
+	Steps []WhyStep
+}
+
+func (s WhyStep) String() string {
+	return s.Name + "@" + s.Version
+}
+
+func (c WhyChain) String() string {
+	steps := make([]string, len(c.Steps))
+	for i, step := range c.Steps {
+		steps[i] = step.String()
+	}
+	return c.Kind + ": " + strings.Join(steps, " > ")
+}
+
+// WhyResult explains why a particular copy of a package is installed
+type WhyResult struct {
+	Package WhyStep
+	Chains  []WhyChain
+}
+
+// BuildDependentsIndex reads every package.json in the package's
+// node_modules folder (including nested node_modules folders) and works out
+// which package each dependency resolves to
+func (m *NpmManager) BuildDependentsIndex(pkg *Package) *DependentsIndex {
+	index := &DependentsIndex{
+		rootPath:   pkg.Path,
+		packages:   map[string]*PackageConfig{},
+		dependents: map[string][]dependent{},
+	}
+
+	nodeModulesDirs := []string{filepath.Join(pkg.Path, "node_modules")}
+	if pkg.IsWorkspaceMember() {
+		// dependencies of workspace members are mostly hoisted to the root
+		nodeModulesDirs = append(nodeModulesDirs, filepath.Join(pkg.WorkspaceRootPath, "node_modules"))
+	}
+	for _, dir := range nodeModulesDirs {
+		m.indexNodeModules(dir, index.packages)
+	}
+
+	for kind, depMap := range map[string]map[string]string{
+		"prod":     pkg.Config.Dependencies,
+		"dev":      pkg.Config.DevDependencies,
+		"optional": pkg.Config.OptionalDependencies,
+		"peer":     pkg.Config.PeerDependencies,
+	} {
+		for name := range depMap {
+			if target := ResolveDep(pkg.Path, name); target != "" {
+				index.dependents[target] = append(index.dependents[target], dependent{kind: kind})
+			}
+		}
+	}
+
+	for path, pkgConfig := range index.packages {
+		fromDir := path
+		if linkPath, err := filepath.EvalSymlinks(path); err == nil {
+			fromDir = linkPath
+		}
+		for _, depMap := range []map[string]string{pkgConfig.Dependencies, pkgConfig.OptionalDependencies, pkgConfig.PeerDependencies} {
+			for name := range depMap {
+				if target := ResolveDep(fromDir, name); target != "" && target != path {
+					index.dependents[target] = append(index.dependents[target], dependent{path: path})
+				}
+			}
+		}
+	}
+
+	return index
+}
+
+func (m *NpmManager) indexNodeModules(nodeModulesDir string, packages map[string]*PackageConfig) {
+	fileInfos, err := ioutil.ReadDir(nodeModulesDir)
+	if err != nil {
+		return
+	}
+
+	for _, fileInfo := range fileInfos {
+		name := fileInfo.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(nodeModulesDir, name)
+		if strings.HasPrefix(name, "@") {
+			m.indexNodeModules(path, packages)
+			continue
+		}
+		if _, ok := packages[path]; ok {
+			continue
+		}
+
+		pkgConfig, err := m.getPackageConfig(path)
+		if err != nil {
+			continue
+		}
+		packages[path] = pkgConfig
+
+		m.indexNodeModules(filepath.Join(path, "node_modules"), packages)
+	}
+}
+
+// Why returns each installed copy of the named package along with the chains
+// of dependencies that lead to it from the root package
+func (index *DependentsIndex) Why(name string) []WhyResult {
+	results := []WhyResult{}
+	for path, pkgConfig := range index.packages {
+		// aliased packages are installed under a different name to their own
+		if pkgConfig.Name != name && installedName(path) != name {
+			continue
+		}
+		target := index.step(path)
+		chains := []WhyChain{}
+		index.collectChains(path, []WhyStep{target}, map[string]bool{}, &chains)
+		sort.Slice(chains, func(i, j int) bool { return chains[i].String() < chains[j].String() })
+		results = append(results, WhyResult{Package: target, Chains: chains})
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Package.Path < results[j].Package.Path })
+	return results
+}
+
+// installedName returns the name a package is installed under e.g. '@scope/name'
+func installedName(path string) string {
+	parent := filepath.Base(filepath.Dir(path))
+	if strings.HasPrefix(parent, "@") {
+		return parent + "/" + filepath.Base(path)
+	}
+	return filepath.Base(path)
+}
+
+func (index *DependentsIndex) step(path string) WhyStep {
+	pkgConfig := index.packages[path]
+	relPath, err := filepath.Rel(index.rootPath, path)
+	if err != nil {
+		relPath = path
+	}
+	return WhyStep{Name: pkgConfig.Name, Version: pkgConfig.Version, Path: relPath}
+}
+
+// collectChains walks up the dependents of the package at the given path until
+// it reaches the root package. steps are in order from the root downwards
+func (index *DependentsIndex) collectChains(path string, steps []WhyStep, visited map[string]bool, chains *[]WhyChain) {
+	if visited[path] {
+		return
+	}
+	visited[path] = true
+	defer delete(visited, path)
+
+	for _, dependent := range index.dependents[path] {
+		if len(*chains) >= maxWhyChains {
+			return
+		}
+		if dependent.path == "" {
+			*chains = append(*chains, WhyChain{Kind: dependent.kind, Steps: steps})
+			continue
+		}
+		newSteps := append([]WhyStep{index.step(dependent.path)}, steps...)
+		index.collectChains(dependent.path, newSteps, visited, chains)
+	}
+}
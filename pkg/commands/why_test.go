@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependentsIndexWhy(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	writePackageTree(t, rootPath, map[string]string{
+		"node_modules/a":                       `{"name": "a", "version": "1.0.0", "dependencies": {"c": "^1.0.0"}}`,
+		"node_modules/@scope/b":                `{"name": "@scope/b", "version": "1.0.0", "dependencies": {"c": "^2.0.0", "a": "^1.0.0"}}`,
+		"node_modules/@scope/b/node_modules/c": `{"name": "c", "version": "2.0.0"}`,
+		"node_modules/c":                       `{"name": "c", "version": "1.0.0", "dependencies": {"a": "^1.0.0"}}`,
+	})
+
+	pkg := &Package{
+		Path: rootPath,
+		Config: PackageConfig{
+			Dependencies:    map[string]string{"a": "^1.0.0"},
+			DevDependencies: map[string]string{"@scope/b": "^1.0.0"},
+		},
+	}
+
+	index := NewDummyNpmManager().BuildDependentsIndex(pkg)
+
+	chainStrings := func(result WhyResult) []string {
+		strs := make([]string, len(result.Chains))
+		for i, chain := range result.Chains {
+			strs[i] = chain.String()
+		}
+		return strs
+	}
+
+	results := index.Why("c")
+	assert.EqualValues(t, 2, len(results))
+
+	assert.EqualValues(t, filepath.FromSlash("node_modules/@scope/b/node_modules/c"), results[0].Package.Path)
+	assert.EqualValues(t, []string{"dev: @scope/b@1.0.0 > c@2.0.0"}, chainStrings(results[0]))
+
+	assert.EqualValues(t, filepath.FromSlash("node_modules/c"), results[1].Package.Path)
+	// a and c depend on each other, which we shouldn't get stuck on
+	assert.EqualValues(t, []string{
+		"dev: @scope/b@1.0.0 > a@1.0.0 > c@1.0.0",
+		"prod: a@1.0.0 > c@1.0.0",
+	}, chainStrings(results[1]))
+
+	assert.EqualValues(t, 0, len(index.Why("nonexistent")))
+}
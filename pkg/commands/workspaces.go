@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExpandWorkspaces takes the patterns from a package's `workspaces` field and
+// returns the paths of the matching workspace packages. Patterns are globs
+// relative to the root package, can use `**` to match any number of
+// directories, and can be negated with a leading `!`.
+func ExpandWorkspaces(rootPath string, patterns []string) []string {
+	included := map[string]bool{}
+
+	for _, pattern := range patterns {
+		exclude := strings.HasPrefix(pattern, "!")
+		pattern = filepath.ToSlash(filepath.Clean(strings.TrimPrefix(pattern, "!")))
+
+		for _, match := range matchWorkspacePattern(rootPath, pattern) {
+			if !FileExists(filepath.Join(match, "package.json")) {
+				continue
+			}
+			if exclude {
+				delete(included, match)
+			} else {
+				included[match] = true
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(included))
+	for path := range included {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func matchWorkspacePattern(rootPath string, pattern string) []string {
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(filepath.Join(rootPath, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil
+		}
+		return matches
+	}
+
+	patternSegments := strings.Split(pattern, "/")
+	matches := []string{}
+	_ = filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		if path != rootPath && (info.Name() == "node_modules" || strings.HasPrefix(info.Name(), ".")) {
+			return filepath.SkipDir
+		}
+		relPath, err := filepath.Rel(rootPath, path)
+		if err != nil || relPath == "." {
+			return nil
+		}
+		if matchSegments(patternSegments, strings.Split(filepath.ToSlash(relPath), "/")) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	return matches
+}
+
+// matchSegments matches path segments against glob segments, where a `**`
+// segment matches zero or more path segments
+func matchSegments(patternSegments []string, pathSegments []string) bool {
+	if len(patternSegments) == 0 {
+		return len(pathSegments) == 0
+	}
+
+	if patternSegments[0] == "**" {
+		for i := 0; i <= len(pathSegments); i++ {
+			if matchSegments(patternSegments[1:], pathSegments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(pathSegments) == 0 {
+		return false
+	}
+
+	matched, err := filepath.Match(patternSegments[0], pathSegments[0])
+	if err != nil || !matched {
+		return false
+	}
+	return matchSegments(patternSegments[1:], pathSegments[1:])
+}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalPackageConfigWorkspaces(t *testing.T) {
+	type scenario struct {
+		config             string
+		expectedWorkspaces []string
+		expectedFiles      []string
+	}
+
+	scenarios := []scenario{
+		{
+			`{"name": "root", "workspaces": ["packages/*", "apps/web"]}`,
+			[]string{"packages/*", "apps/web"},
+			nil,
+		},
+		{
+			`{"name": "root", "workspaces": {"packages": ["packages/**"], "nohoist": ["**/react"]}}`,
+			[]string{"packages/**"},
+			nil,
+		},
+		{
+			`{"name": "root"}`,
+			nil,
+			nil,
+		},
+		{
+			// only workspaces can be given as an object
+			`{"name": "root", "files": {"packages": ["dist"]}}`,
+			nil,
+			nil,
+		},
+	}
+
+	for _, s := range scenarios {
+		pkgConfig, err := UnmarshalPackageConfig(strings.NewReader(s.config), nil)
+		assert.NoError(t, err)
+		assert.EqualValues(t, s.expectedWorkspaces, pkgConfig.Workspaces)
+		assert.EqualValues(t, s.expectedFiles, pkgConfig.Files)
+	}
+}
+
+func TestExpandWorkspaces(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	writePackageTree(t, rootPath, map[string]string{
+		"packages/a":        `{}`,
+		"packages/b":        `{}`,
+		"packages/nested/c": `{}`,
+		"apps/web":          `{}`,
+	})
+	assert.NoError(t, os.MkdirAll(filepath.Join(rootPath, "apps/not-a-package"), 0755))
+
+	type scenario struct {
+		patterns []string
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			[]string{"packages/*"},
+			[]string{"packages/a", "packages/b"},
+		},
+		{
+			[]string{"packages/**", "apps/*"},
+			[]string{"apps/web", "packages/a", "packages/b", "packages/nested/c"},
+		},
+		{
+			[]string{"packages/**", "!packages/b"},
+			[]string{"packages/a", "packages/nested/c"},
+		},
+	}
+
+	for _, s := range scenarios {
+		expected := make([]string, len(s.expected))
+		for i, path := range s.expected {
+			expected[i] = filepath.Join(rootPath, path)
+		}
+		assert.EqualValues(t, expected, ExpandWorkspaces(rootPath, s.patterns))
+	}
+}
+
+func TestGetPackagesWorkspaceMemberBeforeRoot(t *testing.T) {
+	rootPath, err := ioutil.TempDir("", "lazynpm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootPath)
+
+	writePackageTree(t, rootPath, map[string]string{
+		"other":      `{"name": "other"}`,
+		".":          `{"name": "root", "workspaces": ["packages/*"], "packageManager": "pnpm@8.6.0"}`,
+		"packages/a": `{"name": "a"}`,
+	})
+	otherPath := filepath.Join(rootPath, "other")
+	memberPath := filepath.Join(rootPath, "packages", "a")
+
+	manager := NewDummyNpmManager()
+	manager.globalLinkDirs = map[string]string{"npm": "", "yarn": "", "pnpm": ""}
+	pkgs, err := manager.GetPackages([]string{otherPath, memberPath, rootPath}, nil)
+	assert.NoError(t, err)
+
+	type pkgSummary struct {
+		name              string
+		workspaceRootPath string
+		packageManager    string
+	}
+	result := []pkgSummary{}
+	for _, pkg := range pkgs {
+		result = append(result, pkgSummary{name: pkg.Config.Name, workspaceRootPath: pkg.WorkspaceRootPath, packageManager: pkg.PackageManager.Name()})
+	}
+
+	assert.EqualValues(t, []pkgSummary{
+		{name: "other", packageManager: "npm"},
+		{name: "root", packageManager: "pnpm"},
+		{name: "a", workspaceRootPath: rootPath, packageManager: "pnpm"},
+	}, result)
+}
@@ -246,6 +246,12 @@ update:
 reporting: 'undetermined' # one of: 'on' | 'off' | 'undetermined'
 splashUpdatesIndex: 0
 confirmOnQuit: false
+packDestination: '' # a folder to look for tarballs in, besides each package's own folder
+licenses:
+  ## licenses may be SPDX identifiers or patterns like 'GPL-*'
+  allow: [] # if not empty, only these licenses are allowed
+  deny: [] # e.g. ['GPL-*', 'AGPL-*']
+  checkDevDependencies: false # whether dev dependencies need to follow the policy too
 keybinding:
   universal:
     quit: 'q'
@@ -293,6 +299,8 @@ keybinding:
     install: 'i'
     update: 'u'
     cleanInstall: 'I'
+    toggleSelected: '<c-space>'
+    toggleRangeSelect: 'V'
   status:
     checkForUpdate: 'u'
   main:
@@ -304,8 +312,26 @@ keybinding:
     build: 'b'
     pack: 'p'
     publish: 'P'
+    setPackageManager: 'm'
+    version: 'v'
+    packList: 'f'
+    distTags: 't'
   dependencies:
     changeType: 't'
+    upgrade: 'U'
+    why: 'w'
+    whySearch: '<c-w>'
+    viewDuplicates: 'D'
+    dedupe: 'd'
+    sortBySize: 's'
+    licenses: 'L'
+    viewInfo: 'v'
+    deprecations: 'X'
+  tarballs:
+    compare: 'c'
+  vulnerabilities:
+    fix: 'f'
+    forceFix: 'F'
 `)
 }
 
@@ -314,6 +340,9 @@ keybinding:
 type AppState struct {
 	LastUpdateCheck int64
 	RecentPackages  []string
+	// maps package paths to the package manager the user wants to use for
+	// that package, for when we can't detect it ourselves
+	PackageManagers map[string]string
 }
 
 func getDefaultAppState() []byte {
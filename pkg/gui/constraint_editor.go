@@ -0,0 +1,284 @@
+package gui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/gui/presentation"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// max number of versions we offer in the version picker, newest first
+const maxPickerVersions = 50
+
+// availableVersions are the versions of a dependency the user can pick from
+type availableVersions struct {
+	// versions are newest first
+	versions []string
+	// registryPackage is nil if we couldn't reach the registry, in which case
+	// versions come from the lockfile
+	registryPackage *commands.RegistryPackage
+}
+
+// handleEditDepConstraints uses the guided editor for a single dependency.
+// For several we can only offer to restyle their constraints around the
+// versions already installed
+func (gui *Gui) handleEditDepConstraints(deps []*commands.Dependency) error {
+	if len(deps) == 1 {
+		return gui.handleEditDepConstraint(deps[0])
+	}
+	return gui.createBulkConstraintStyleMenu(deps)
+}
+
+// handleEditDepConstraint walks the user through picking a version and then a
+// style of constraint for it, showing what the new constraint would mean for
+// what's installed before saving
+func (gui *Gui) handleEditDepConstraint(dep *commands.Dependency) error {
+	pkg := gui.currentPackage()
+	return gui.WithWaitingStatus("fetching versions", func() error {
+		available := gui.getAvailableVersions(pkg, dep)
+
+		gui.g.Update(func(*gocui.Gui) error {
+			return gui.createConstraintVersionMenu(dep, available)
+		})
+		return nil
+	})
+}
+
+// getAvailableVersions asks the registry for the dependency's versions,
+// falling back to what's in the lockfile if we can't reach it
+func (gui *Gui) getAvailableVersions(pkg *commands.Package, dep *commands.Dependency) *availableVersions {
+	registryPackage, err := gui.NpmManager.GetRegistryPackage(pkg, dep.Name)
+	if err == nil {
+		versions := make([]string, len(registryPackage.Versions))
+		for i, version := range registryPackage.Versions {
+			versions[i] = version.Version
+		}
+		return &availableVersions{versions: versions, registryPackage: registryPackage}
+	}
+	gui.Log.Warn(err)
+
+	versions := []string{}
+	lockfile, err := gui.NpmManager.GetLockfile(pkg)
+	if err != nil {
+		gui.Log.Error(err)
+	} else if lockfile != nil {
+		versions = lockfile.Versions(dep.Name)
+	}
+	if installed := installedVersion(dep); installed != "" && !utils.IncludesString(versions, installed) {
+		versions = append([]string{installed}, versions...)
+	}
+	return &availableVersions{versions: versions}
+}
+
+// installedVersion is empty if the dependency isn't installed, or is linked
+// to a local package which the constraint doesn't apply to
+func installedVersion(dep *commands.Dependency) string {
+	if dep.PackageConfig == nil || dep.Linked() {
+		return ""
+	}
+	return dep.PackageConfig.Version
+}
+
+func (gui *Gui) createConstraintVersionMenu(dep *commands.Dependency, available *availableVersions) error {
+	menuItems := []*menuItem{
+		{
+			displayStrings: []string{"enter constraint", utils.ColoredString("e.g. a range, dist-tag, or git url", color.FgHiBlack)},
+			onPress: func() error {
+				return gui.promptConstraint(dep, dep.Constraint, available)
+			},
+		},
+	}
+
+	installed := installedVersion(dep)
+	versions := available.versions
+	if len(versions) > maxPickerVersions {
+		versions = versions[:maxPickerVersions]
+	}
+	for _, version := range versions {
+		version := version
+		notes := []string{}
+		if available.registryPackage != nil {
+			if tags := available.registryPackage.TagsForVersion(version); len(tags) > 0 {
+				notes = append(notes, utils.ColoredString(strings.Join(tags, ", "), color.FgCyan))
+			}
+			if registryVersion := available.registryPackage.Version(version); registryVersion != nil && registryVersion.Deprecated != "" {
+				notes = append(notes, utils.ColoredString("deprecated", color.FgRed))
+			}
+		}
+		if version == installed {
+			notes = append(notes, utils.ColoredString("installed", color.FgGreen))
+		}
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: []string{version, strings.Join(notes, " ")},
+			onPress: func() error {
+				return gui.createConstraintStyleMenu(dep, version, available)
+			},
+		})
+	}
+
+	title := fmt.Sprintf("Pick a version of %s", dep.Name)
+	if available.registryPackage == nil {
+		title += " (from lockfile)"
+	}
+	return gui.createMenu(title, menuItems, createMenuOptions{showCancel: true})
+}
+
+func (gui *Gui) createConstraintStyleMenu(dep *commands.Dependency, version string, available *availableVersions) error {
+	installed := installedVersion(dep)
+	menuItems := []*menuItem{}
+	for _, style := range commands.ConstraintStyles() {
+		style := style
+		constraint, err := style.Format(version)
+		if err != nil {
+			return gui.createErrorPanel(err.Error())
+		}
+		preview := commands.PreviewConstraint(constraint, installed, available.versions)
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: []string{
+				style.Name,
+				utils.ColoredString(constraint, color.FgMagenta),
+				style.Description,
+				presentation.ConstraintPreviewSummary(preview),
+			},
+			onPress: func() error {
+				if style.Name == "range" {
+					return gui.promptConstraint(dep, constraint, available)
+				}
+				return gui.confirmConstraintChange(dep, constraint, available)
+			},
+		})
+	}
+
+	return gui.createMenu(fmt.Sprintf("Constraint for %s@%s", dep.Name, version), menuItems, createMenuOptions{showCancel: true})
+}
+
+// createBulkConstraintStyleMenu offers each style of constraint to apply to
+// all of the dependencies. Dependencies without an installed or locked version
+// to base the constraint on are left alone
+func (gui *Gui) createBulkConstraintStyleMenu(deps []*commands.Dependency) error {
+	menuItems := []*menuItem{}
+	for _, style := range commands.ConstraintStyles() {
+		changed := []*commands.Dependency{}
+		constraints := []string{}
+		skipped := []*commands.Dependency{}
+		for _, dep := range deps {
+			constraint, err := style.Format(currentVersion(dep))
+			if err != nil {
+				skipped = append(skipped, dep)
+				continue
+			}
+			changed = append(changed, dep)
+			constraints = append(constraints, constraint)
+		}
+
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: []string{style.Name, style.Description},
+			onPress: func() error {
+				return gui.confirmConstraintChanges(changed, constraints, skipped)
+			},
+		})
+	}
+
+	return gui.createMenu(fmt.Sprintf("Constraint style for %d dependencies", len(deps)), menuItems, createMenuOptions{showCancel: true})
+}
+
+// currentVersion falls back to the locked version if the dependency isn't
+// installed, so that we can still base a constraint on it
+func currentVersion(dep *commands.Dependency) string {
+	if installed := installedVersion(dep); installed != "" {
+		return installed
+	}
+	if dep.Locked != nil && !dep.Linked() {
+		return dep.Locked.Version
+	}
+	return ""
+}
+
+func (gui *Gui) confirmConstraintChanges(deps []*commands.Dependency, constraints []string, skipped []*commands.Dependency) error {
+	if len(deps) == 0 {
+		return gui.createErrorPanel("none of these dependencies have a version to base a constraint on")
+	}
+
+	return gui.createConfirmationPanel(createConfirmationPanelOpts{
+		returnToView:       gui.getDepsView(),
+		returnFocusOnClose: true,
+		title:              "Edit constraints",
+		prompt:             presentation.ConstraintChangesOutput(deps, constraints, skipped),
+		handleConfirm: func() error {
+			gui.depsListView().clearSelection()
+			for i, dep := range deps {
+				packageConfigPath := filepath.Join(dep.ParentPackagePath, "package.json")
+				if err := gui.NpmManager.EditDepConstraint(dep, packageConfigPath, constraints[i]); err != nil {
+					return gui.finalStep(err)
+				}
+			}
+			if err := gui.finalStep(nil); err != nil {
+				return err
+			}
+			return gui.offerInstall(deps[0].ID(), nil)
+		},
+	})
+}
+
+func (gui *Gui) promptConstraint(dep *commands.Dependency, initialConstraint string, available *availableVersions) error {
+	return gui.createPromptPanel(gui.getDepsView(), "Edit constraint", initialConstraint, func(input string) error {
+		return gui.confirmConstraintChange(dep, input, available)
+	})
+}
+
+// confirmConstraintChange shows whether the installed version still satisfies
+// the new constraint before saving it, then offers to install so that
+// node_modules and the lockfile catch up
+func (gui *Gui) confirmConstraintChange(dep *commands.Dependency, constraint string, available *availableVersions) error {
+	preview := commands.PreviewConstraint(constraint, installedVersion(dep), available.versions)
+	if preview.Constraint == "" {
+		return gui.createErrorPanel("constraint cannot be blank")
+	}
+
+	return gui.createConfirmationPanel(createConfirmationPanelOpts{
+		returnToView:       gui.getDepsView(),
+		returnFocusOnClose: true,
+		title:              "Edit constraint",
+		prompt:             presentation.ConstraintPreviewOutput(dep.Name, dep.Constraint, preview),
+		handleConfirm: func() error {
+			gui.depsListView().clearSelection()
+			packageConfigPath := filepath.Join(dep.ParentPackagePath, "package.json")
+			if err := gui.finalStep(gui.NpmManager.EditDepConstraint(dep, packageConfigPath, preview.Constraint)); err != nil {
+				return err
+			}
+			unsatisfied := []string{}
+			if preview.Installed != "" && preview.Err == nil && !preview.Satisfied {
+				unsatisfied = append(unsatisfied, dep.Name)
+			}
+			return gui.offerInstall(dep.ID(), unsatisfied)
+		},
+	})
+}
+
+// offerInstall asks to install so that node_modules and the lockfile catch up
+// with package.json, mentioning the dependencies whose installed version no
+// longer satisfies their constraint
+func (gui *Gui) offerInstall(contextKey string, unsatisfied []string) error {
+	pkg := gui.currentPackage()
+	cmdStr := pkg.PackageManager.Install(gui.cmdOpts(pkg))
+
+	prompt := fmt.Sprintf("Run %s so that node_modules and the lockfile match package.json?", utils.ColoredString(cmdStr, color.FgYellow))
+	if len(unsatisfied) > 0 {
+		prompt = fmt.Sprintf("The installed version of %s no longer satisfies its constraint. %s", strings.Join(unsatisfied, ", "), prompt)
+	}
+
+	return gui.createConfirmationPanel(createConfirmationPanelOpts{
+		returnToView:       gui.getDepsView(),
+		returnFocusOnClose: true,
+		title:              "Install",
+		prompt:             prompt,
+		handleConfirm: func() error {
+			return gui.newMainCommand(cmdStr, contextKey, newMainCommandOptions{onSuccess: gui.invalidateBackgroundChecks})
+		},
+	})
+}
@@ -2,7 +2,8 @@ package gui
 
 import (
 	"fmt"
-	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/jesseduffield/gocui"
@@ -21,6 +22,13 @@ func (gui *Gui) getSelectedDependency() *commands.Dependency {
 }
 
 func (gui *Gui) handleDepSelect(g *gocui.Gui, v *gocui.View) error {
+	if gui.inDepTree() {
+		return gui.handleDepTreeNodeSelect(g, v)
+	}
+	if gui.inDuplicates() {
+		return gui.handleDuplicateSelect(g, v)
+	}
+
 	dep := gui.getSelectedDependency()
 	if dep == nil {
 		gui.printToMain(gui.Tr.SLocalize("NoDependencies"))
@@ -33,14 +41,62 @@ func (gui *Gui) handleDepSelect(g *gocui.Gui, v *gocui.View) error {
 		if dep.Linked() {
 			summary = fmt.Sprintf("%s\nLinked to: %s", summary, utils.ColoredString(dep.LinkPath, color.FgCyan))
 		}
+		if dep.Outdated != nil {
+			summary = fmt.Sprintf("%s\nWanted: %s", summary, utils.ColoredString(dep.Outdated.Wanted, presentation.VersionGapColor(dep.Outdated.Current, dep.Outdated.Wanted)))
+			summary = fmt.Sprintf("%s\nLatest: %s", summary, utils.ColoredString(dep.Outdated.Latest, presentation.VersionGapColor(dep.Outdated.Current, dep.Outdated.Latest)))
+		}
+		if dep.DiskUsage != nil {
+			summary = fmt.Sprintf("%s\nSize: %s", summary, utils.ColoredString(presentation.FormatSize(dep.DiskUsage.Size), presentation.SizeColor(dep.DiskUsage.Size)))
+		}
+		if dep.PackageConfig.License != "" {
+			summary = fmt.Sprintf("%s\nLicense: %s", summary, utils.ColoredString(dep.PackageConfig.License, color.FgCyan))
+		}
+		for _, licensedPackage := range dep.LicenseViolations {
+			summary = fmt.Sprintf("%s\nLicense violation: %s", summary, presentation.LicenseViolationString(licensedPackage, dep.Name))
+		}
+		for _, violation := range dep.EngineViolations {
+			summary = fmt.Sprintf("%s\nEngines: %s", summary, presentation.EngineViolationString(violation))
+		}
+		if dep.Deprecated != "" {
+			summary = fmt.Sprintf("%s\nDeprecated: %s", summary, utils.ColoredString(dep.Deprecated, color.FgRed))
+		}
+		for _, deprecation := range dep.DeprecatedDependencies {
+			summary = fmt.Sprintf("%s\nDeprecated dependency: %s", summary, presentation.DeprecationString(deprecation))
+		}
+		summary += lockedSummary(dep)
 		gui.renderString("secondary", summary)
 	} else {
-		gui.renderString("secondary", "dependency not present in node_modules")
+		gui.renderString("secondary", "dependency not present in node_modules"+lockedSummary(dep))
+	}
+	if gui.State.SortDepsBySize {
+		gui.printToMain(presentation.DiskUsageOutput(dep))
+		return nil
 	}
 	gui.activateContextView(dep.ID())
 	return nil
 }
 
+func lockedSummary(dep *commands.Dependency) string {
+	if dep.Locked == nil {
+		if dep.LockDrift {
+			return fmt.Sprintf("\n%s", utils.ColoredString("Lock drift: not in lockfile", color.FgRed))
+		}
+		return ""
+	}
+
+	summary := fmt.Sprintf("\nLocked: %s", utils.ColoredString(dep.Locked.Version, color.FgYellow))
+	if dep.Locked.Resolved != "" {
+		summary = fmt.Sprintf("%s\nResolved: %s", summary, dep.Locked.Resolved)
+	}
+	if dep.Locked.Integrity != "" {
+		summary = fmt.Sprintf("%s\nIntegrity: %s", summary, dep.Locked.Integrity)
+	}
+	if dep.LockDrift {
+		summary = fmt.Sprintf("%s\n%s", summary, utils.ColoredString("Lock drift: installed version does not match lockfile", color.FgRed))
+	}
+	return summary
+}
+
 // linkPathMap returns the set of link paths of the current package's dependencies
 func (gui *Gui) linkPathMap() map[string]bool {
 	linkPathMap := map[string]bool{}
@@ -52,69 +108,276 @@ func (gui *Gui) linkPathMap() map[string]bool {
 	return linkPathMap
 }
 
-func (gui *Gui) handleDepInstall(dep *commands.Dependency) error {
-	cmdStr := fmt.Sprintf("npm install %s", dep.Name)
-	return gui.newMainCommand(cmdStr, dep.ID(), newMainCommandOptions{})
+// packageManager returns the package manager of the current package, which is
+// the one we use for anything relating to dependencies
+func (gui *Gui) packageManager() commands.PackageManager {
+	return gui.currentPackage().PackageManager
 }
 
-func (gui *Gui) handleDepUpdate(dep *commands.Dependency) error {
-	cmdStr := fmt.Sprintf("npm update %s", dep.Name)
-	return gui.newMainCommand(cmdStr, dep.ID(), newMainCommandOptions{})
+func (gui *Gui) handleDepInstall(dep *commands.Dependency) error {
+	cmdStr := gui.packageManager().AddDeps("", dep.Name)
+	return gui.newMainCommand(cmdStr, dep.ID(), newMainCommandOptions{onSuccess: gui.invalidateBackgroundChecks})
 }
 
-func (gui *Gui) handleOpenDepPackageConfig(dep *commands.Dependency) error {
-	if dep.PackageConfig == nil {
-		return gui.createErrorPanel("dependency not in node_modules")
+func depNames(deps []*commands.Dependency) []string {
+	names := make([]string, len(deps))
+	for i, dep := range deps {
+		names[i] = dep.Name
 	}
+	return names
+}
 
-	return gui.openFile(dep.ConfigPath())
+// commonKind is empty if the dependencies aren't all of the same kind, which
+// leaves it up to the package manager
+func commonKind(deps []*commands.Dependency) string {
+	for _, dep := range deps[1:] {
+		if dep.Kind != deps[0].Kind {
+			return ""
+		}
+	}
+	return deps[0].Kind
 }
 
-func (gui *Gui) handleDepUninstall(dep *commands.Dependency) error {
-	var menuItems []*menuItem
+// handlers acting on several dependencies store their command against the
+// first of them
 
-	if dep.Kind == "peer" {
-		// I have no idea how peer dependencies work, so we're just using the one option here
-		uninstallStr := fmt.Sprintf("npm uninstall %s", dep.Name)
+func (gui *Gui) handleDepUpdate(deps []*commands.Dependency) error {
+	cmdStr := gui.packageManager().UpdateDeps(depNames(deps)...)
+	gui.depsListView().clearSelection()
+	return gui.newMainCommand(cmdStr, deps[0].ID(), newMainCommandOptions{onSuccess: gui.invalidateBackgroundChecks})
+}
 
-		menuItems = []*menuItem{
-			{
-				displayStrings: []string{"uninstall", utils.ColoredString(uninstallStr, color.FgYellow)},
-				onPress: func() error {
-					return gui.newMainCommand(uninstallStr, dep.ID(), newMainCommandOptions{})
-				},
+func (gui *Gui) handleDepUpgrade(dep *commands.Dependency) error {
+	if dep.Outdated == nil {
+		return gui.createErrorPanel("dependency is up to date (or we haven't finished checking)")
+	}
+
+	pm := gui.packageManager()
+	menuItems := []*menuItem{}
+	for _, target := range []struct {
+		name    string
+		version string
+		cmdStr  string
+	}{
+		// updating within the constraint doesn't touch package.json
+		{name: "wanted", version: dep.Outdated.Wanted, cmdStr: pm.UpdateDeps(dep.Name)},
+		{name: "latest", version: dep.Outdated.Latest, cmdStr: pm.AddDeps(dep.Kind, fmt.Sprintf("%s@%s", dep.Name, dep.Outdated.Latest))},
+	} {
+		if target.version == "" {
+			continue
+		}
+		cmdStr := target.cmdStr
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: []string{
+				fmt.Sprintf("update to %s (%s)", target.name, target.version),
+				utils.ColoredString(cmdStr, color.FgYellow),
 			},
+			onPress: func() error {
+				return gui.newMainCommand(cmdStr, dep.ID(), newMainCommandOptions{onSuccess: gui.invalidateBackgroundChecks})
+			},
+		})
+	}
+
+	return gui.createMenu("Update dependency", menuItems, createMenuOptions{showCancel: true})
+}
+
+// refreshOutdated asks the package manager which of the current package's
+// dependencies are outdated. This hits the registry so we do it in the background
+func (gui *Gui) refreshOutdated() {
+	pkg := gui.currentPackage()
+	gui.State.OutdatedPackagePath = pkg.Path
+	gui.State.Outdated = nil
+	opts := gui.cmdOpts(pkg)
+
+	_ = gui.WithWaitingStatus("checking for outdated dependencies", func() error {
+		outdated, err := gui.NpmManager.GetOutdated(pkg, opts)
+		if err != nil {
+			return err
 		}
-	} else {
-		kindMap := map[string]string{
-			"prod":     " --save",
-			"dev":      " --save-dev",
-			"optional": " --save-optional",
+
+		gui.g.Update(func(*gocui.Gui) error {
+			// the user may have switched packages in the meantime
+			if gui.State.OutdatedPackagePath != pkg.Path {
+				return nil
+			}
+			gui.State.Outdated = outdated
+			return gui.refreshPackages()
+		})
+		return nil
+	})
+}
+
+// sortDepsBySize puts the heaviest dependencies first, leaving those whose
+// size we don't know yet at the end in their original order
+func sortDepsBySize(deps []*commands.Dependency) {
+	size := func(dep *commands.Dependency) int64 {
+		if dep.DiskUsage == nil {
+			return -1
 		}
+		return dep.DiskUsage.Size
+	}
+	sort.SliceStable(deps, func(i, j int) bool { return size(deps[i]) > size(deps[j]) })
+}
 
-		uninstallCmdStr := fmt.Sprintf("npm uninstall --no-save %s", dep.Name)
-		uninstallAndSaveCmdStr := fmt.Sprintf("npm uninstall%s %s", kindMap[dep.Kind], dep.Name)
+func (gui *Gui) handleToggleSortDepsBySize() error {
+	selectedDep := gui.getSelectedDependency()
 
-		menuItems = []*menuItem{
-			{
-				displayStrings: []string{"uninstall and save", utils.ColoredString(uninstallAndSaveCmdStr, color.FgYellow)},
-				onPress: func() error {
-					return gui.newMainCommand(uninstallAndSaveCmdStr, dep.ID(), newMainCommandOptions{})
-				},
-			},
-			{
-				displayStrings: []string{"just uninstall", utils.ColoredString(uninstallCmdStr, color.FgYellow)},
-				onPress: func() error {
-					return gui.newMainCommand(uninstallCmdStr, dep.ID(), newMainCommandOptions{})
-				},
+	gui.State.SortDepsBySize = !gui.State.SortDepsBySize
+	if err := gui.refreshPackages(); err != nil {
+		return err
+	}
+
+	// keeping the same dependency selected now that it's moved
+	for i, dep := range gui.State.Deps {
+		if selectedDep != nil && dep.Name == selectedDep.Name && dep.Kind == selectedDep.Kind {
+			gui.State.Panels.Deps.SelectedLine = i
+			break
+		}
+	}
+	depsView := gui.getDepsView()
+	depsView.FocusPoint(0, gui.State.Panels.Deps.SelectedLine)
+
+	return gui.handleDepSelect(gui.g, depsView)
+}
+
+// invalidateBackgroundChecks means we'll redo everything we work out about
+// the current package's dependencies in the background on the next refresh,
+// e.g. after dependencies are installed or removed
+func (gui *Gui) invalidateBackgroundChecks() {
+	gui.State.OutdatedPackagePath = ""
+	gui.State.AuditPackagePath = ""
+	gui.State.NodeModulesPackagePath = ""
+}
+
+// handleViewDepInfo shows what the registry knows about the dependency, so
+// that the user can pick a version without having to run `npm view`
+func (gui *Gui) handleViewDepInfo(dep *commands.Dependency) error {
+	pkg := gui.currentPackage()
+	return gui.WithWaitingStatus("fetching package info", func() error {
+		registryPackage, err := gui.NpmManager.GetRegistryPackage(pkg, dep.Name)
+		if err != nil {
+			return err
+		}
+		installedVersion := ""
+		if dep.PackageConfig != nil {
+			installedVersion = dep.PackageConfig.Version
+		}
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.printToMain(presentation.RegistryPackageOutput(registryPackage, installedVersion, dep.Constraint))
+			return nil
+		})
+		return nil
+	})
+}
+
+func (gui *Gui) handleWhyDep(dep *commands.Dependency) error {
+	return gui.explainWhyInstalled(dep.Name)
+}
+
+func (gui *Gui) handleWhyDepNode(node *commands.DepNode) error {
+	return gui.explainWhyInstalled(node.Name)
+}
+
+// handleWhySearch explains why the package being searched for in the deps
+// panel is installed. This lets you ask about transitive dependencies, which
+// never show up in the deps panel itself. Works both while typing the search
+// and after it's been submitted
+func (gui *Gui) handleWhySearch(g *gocui.Gui, v *gocui.View) error {
+	if !gui.isSearchingDeps() {
+		return nil
+	}
+
+	name := gui.State.Searching.searchString
+	if v.Name() == "search" {
+		name = gui.getSearchView().Buffer()
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+
+	if err := gui.handleSearchEscape(g, v); err != nil {
+		return err
+	}
+	return gui.explainWhyInstalled(name)
+}
+
+// explainWhyInstalled prints every chain of dependencies leading from the
+// current package to the given package. Reading all of node_modules can take
+// a while so we do it in the background
+func (gui *Gui) explainWhyInstalled(name string) error {
+	pkg := gui.currentPackage()
+	return gui.WithWaitingStatus("reading node_modules", func() error {
+		index := gui.NpmManager.BuildDependentsIndex(pkg)
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.printToMain(presentation.WhyOutput(name, index.Why(name)))
+			return nil
+		})
+		return nil
+	})
+}
+
+func (gui *Gui) handleOpenDepPackageConfig(dep *commands.Dependency) error {
+	if dep.PackageConfig == nil {
+		return gui.createErrorPanel("dependency not in node_modules")
+	}
+
+	return gui.openFile(dep.ConfigPath())
+}
+
+func (gui *Gui) handleDepUninstall(deps []*commands.Dependency) error {
+	pm := gui.packageManager()
+	names := depNames(deps)
+	uninstallAndSaveCmdStr := pm.RemoveDeps(commonKind(deps), names...)
+
+	menuItems := []*menuItem{
+		{
+			displayStrings: []string{"uninstall and save", utils.ColoredString(uninstallAndSaveCmdStr, color.FgYellow)},
+			onPress: func() error {
+				gui.depsListView().clearSelection()
+				return gui.newMainCommand(uninstallAndSaveCmdStr, deps[0].ID(), newMainCommandOptions{onSuccess: gui.invalidateBackgroundChecks})
 			},
+		},
+	}
+
+	// npm installs peer dependencies automatically, so uninstalling one without
+	// removing it from package.json would only last until the next install
+	includesPeer := false
+	for _, dep := range deps {
+		if dep.Kind == "peer" {
+			includesPeer = true
 		}
 	}
+	uninstallCmdStr := pm.RemoveDepsWithoutSaving(names...)
+	if !includesPeer && uninstallCmdStr != "" {
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: []string{"just uninstall", utils.ColoredString(uninstallCmdStr, color.FgYellow)},
+			onPress: func() error {
+				gui.depsListView().clearSelection()
+				return gui.newMainCommand(uninstallCmdStr, deps[0].ID(), newMainCommandOptions{onSuccess: gui.invalidateBackgroundChecks})
+			},
+		})
+	}
 
-	return gui.createMenu("Uninstall dependency", menuItems, createMenuOptions{showCancel: true})
+	title := "Uninstall dependency"
+	if len(deps) > 1 {
+		title = fmt.Sprintf("Uninstall %d dependencies", len(deps))
+	}
+	return gui.createMenu(title, menuItems, createMenuOptions{showCancel: true})
 }
 
 func (gui *Gui) selectedDepID() string {
+	if gui.inDepTree() {
+		node := gui.getSelectedDepNode()
+		if node == nil {
+			return ""
+		}
+		return node.ID()
+	}
+	if gui.inDuplicates() {
+		return gui.dedupeContextID()
+	}
+
 	selectedDep := gui.getSelectedDependency()
 	if selectedDep == nil {
 		return ""
@@ -134,19 +397,21 @@ func (gui *Gui) wrappedDependencyHandler(f func(*commands.Dependency) error) fun
 	})
 }
 
-func (gui *Gui) handleChangeDepType(dep *commands.Dependency) error {
+func (gui *Gui) handleChangeDepType(deps []*commands.Dependency) error {
 	kindKeyMap := commands.KindKeyMap()
 	kindFlags := commands.KindFlags()
 	menuItems := make([]*menuItem, 0, len(kindFlags))
 	for _, kindFlag := range kindFlags {
 		kindFlag := kindFlag
-		cmdStr := fmt.Sprintf("npm install %s %s", kindFlag.Flag, dep.Name)
+		cmdStr := gui.packageManager().AddDeps(kindFlag.Kind, depNames(deps)...)
 		menuItems = append(menuItems, &menuItem{
 			displayStrings: []string{kindKeyMap[kindFlag.Kind], utils.ColoredString(cmdStr, color.FgYellow)},
 			onPress: func() error {
-				return gui.newMainCommand(cmdStr, dep.ID(), newMainCommandOptions{onSuccess: func() {
+				gui.depsListView().clearSelection()
+				return gui.newMainCommand(cmdStr, deps[0].ID(), newMainCommandOptions{onSuccess: func() {
+					gui.invalidateBackgroundChecks()
 					for i, newDep := range gui.State.Deps {
-						if newDep.Name == dep.Name && newDep.Kind == kindFlag.Kind {
+						if newDep.Name == deps[0].Name && newDep.Kind == kindFlag.Kind {
 							gui.State.Panels.Deps.SelectedLine = i
 							gui.refreshDepsView()
 							break
@@ -157,16 +422,20 @@ func (gui *Gui) handleChangeDepType(dep *commands.Dependency) error {
 		})
 	}
 
-	return gui.createMenu("Change dependency type", menuItems, createMenuOptions{showCancel: true})
+	title := "Change dependency type"
+	if len(deps) > 1 {
+		title = fmt.Sprintf("Change type of %d dependencies", len(deps))
+	}
+	return gui.createMenu(title, menuItems, createMenuOptions{showCancel: true})
 }
 
 // this is admittedly a little weird. We're going to store the command against
 // the dep where you initiated the command, but it has nothing to do with that dep.
 func (gui *Gui) handleAddDependency(dep *commands.Dependency) error {
-	prompt := func(cmdStr string) error {
+	pm := gui.packageManager()
+	prompt := func(kind string) error {
 		return gui.createPromptPanel(gui.getDepsView(), "enter dependency name", "", func(input string) error {
-			newCmdStr := fmt.Sprintf("%s %s", cmdStr, input)
-			return gui.newMainCommand(newCmdStr, dep.ID(), newMainCommandOptions{})
+			return gui.newMainCommand(pm.AddDeps(kind, input), dep.ID(), newMainCommandOptions{onSuccess: gui.invalidateBackgroundChecks})
 		})
 	}
 
@@ -175,11 +444,11 @@ func (gui *Gui) handleAddDependency(dep *commands.Dependency) error {
 	menuItems := make([]*menuItem, 0, len(kindFlags))
 	for _, kindFlag := range kindFlags {
 		kindFlag := kindFlag
-		cmdStr := fmt.Sprintf("npm install %s", kindFlag.Flag)
+		cmdStr := pm.AddDeps(kindFlag.Kind)
 		menuItems = append(menuItems, &menuItem{
 			displayStrings: []string{kindKeyMap[kindFlag.Kind], utils.ColoredString(cmdStr, color.FgYellow)},
 			onPress: func() error {
-				return prompt(cmdStr)
+				return prompt(kindFlag.Kind)
 			},
 		})
 	}
@@ -187,15 +456,18 @@ func (gui *Gui) handleAddDependency(dep *commands.Dependency) error {
 	return gui.createMenu("Install dependency to:", menuItems, createMenuOptions{showCancel: true})
 }
 
-func (gui *Gui) handleEditDepConstraint(dep *commands.Dependency) error {
-	return gui.createPromptPanel(gui.getDepsView(), "Edit constraint", dep.Constraint, func(input string) error {
-
-		packageConfigPath := filepath.Join(dep.ParentPackagePath, "package.json")
-		return gui.finalStep(gui.NpmManager.EditDepConstraint(dep, packageConfigPath, input))
-	})
-}
-
 func (gui *Gui) refreshDepsView() {
-	displayStrings := presentation.GetDependencyListDisplayStrings(gui.State.Deps, gui.State.CommandViewMap, gui.getLeftSideWidth() > 70)
-	gui.renderDisplayStrings(gui.getDepsView(), displayStrings)
+	if gui.inDepTree() {
+		displayStrings := presentation.GetDepTreeDisplayStrings(gui.getVisibleDepNodes())
+		gui.renderDisplayStrings(gui.getDepsView(), displayStrings)
+		return
+	}
+	if gui.inDuplicates() {
+		displayStrings := presentation.GetDuplicateListDisplayStrings(gui.State.Duplicates)
+		gui.renderDisplayStrings(gui.getDepsView(), displayStrings)
+		return
+	}
+
+	displayStrings := presentation.GetDependencyListDisplayStrings(gui.State.Deps, gui.State.CommandViewMap, gui.getLeftSideWidth() > 70, gui.highlightedDepNames())
+	gui.renderDisplayStrings(gui.getDepsView(), markSelected(displayStrings, gui.depsListView()))
 }
@@ -0,0 +1,144 @@
+package gui
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/gui/presentation"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// the dependency tree is shown in the deps view under its own context, so that
+// the deps view's usual keybindings don't apply while we're in it
+
+const DEP_TREE_CONTEXT = "tree"
+
+func (gui *Gui) inDepTree() bool {
+	return gui.State.DepTree != nil
+}
+
+func (gui *Gui) getVisibleDepNodes() []*commands.DepNode {
+	if gui.State.DepTree == nil {
+		return nil
+	}
+	return gui.State.DepTree.Flatten()
+}
+
+func (gui *Gui) getSelectedDepNode() *commands.DepNode {
+	nodes := gui.getVisibleDepNodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[gui.State.Panels.DepTree.SelectedLine]
+}
+
+func (gui *Gui) handleDepTreeNodeSelect(g *gocui.Gui, v *gocui.View) error {
+	node := gui.getSelectedDepNode()
+	if node == nil {
+		return nil
+	}
+
+	summary := ""
+	if node.PackageConfig != nil {
+		summary = presentation.PackageSummary(*node.PackageConfig) + "\n"
+	}
+	summary = fmt.Sprintf("%sRequired: %s", summary, utils.ColoredString(node.Constraint, color.FgMagenta))
+	if node.Missing() {
+		summary = fmt.Sprintf("%s\n%s", summary, utils.ColoredString("not found in any node_modules folder", color.FgRed))
+	} else {
+		summary = fmt.Sprintf("%s\nPath: %s", summary, utils.ColoredString(node.Path, color.FgCyan))
+	}
+	if node.Linked() {
+		summary = fmt.Sprintf("%s\nLinked to: %s", summary, utils.ColoredString(node.LinkPath, color.FgCyan))
+	}
+	if node.Deduped {
+		summary = fmt.Sprintf("%s\n%s", summary, "deduped: this package appears elsewhere in the tree")
+	}
+	gui.renderString("secondary", summary)
+	gui.activateContextView(node.ID())
+	return nil
+}
+
+func (gui *Gui) wrappedDepNodeHandler(f func(*commands.DepNode) error) func(*gocui.Gui, *gocui.View) error {
+	return gui.wrappedHandler(func() error {
+		node := gui.getSelectedDepNode()
+		if node == nil {
+			return nil
+		}
+
+		return gui.finalStep(f(node))
+	})
+}
+
+func (gui *Gui) handleOpenDepTree(dep *commands.Dependency) error {
+	root := gui.NpmManager.NewDepTree(dep)
+	if err := gui.NpmManager.LoadDepNodeChildren(root); err != nil {
+		return err
+	}
+	root.Expanded = true
+
+	gui.State.DepTree = root
+	gui.State.Panels.DepTree.SelectedLine = 0
+
+	depsView := gui.getDepsView()
+	depsView.Context = DEP_TREE_CONTEXT
+	depsView.Title = fmt.Sprintf("%s: %s", gui.Tr.SLocalize("DependencyTreeTitle"), dep.Name)
+
+	gui.refreshDepsView()
+	return gui.handleDepTreeNodeSelect(gui.g, depsView)
+}
+
+func (gui *Gui) handleExitDepTree() error {
+	gui.State.DepTree = nil
+
+	depsView := gui.getDepsView()
+	depsView.Context = ""
+	depsView.Title = gui.Tr.SLocalize("DepsTitle")
+
+	gui.refreshDepsView()
+	return gui.handleDepSelect(gui.g, depsView)
+}
+
+func (gui *Gui) handleToggleDepNode(node *commands.DepNode) error {
+	if node.Expanded {
+		return gui.handleCollapseDepNode(node)
+	}
+	return gui.handleExpandDepNode(node)
+}
+
+func (gui *Gui) handleExpandDepNode(node *commands.DepNode) error {
+	if !node.Expandable() {
+		return nil
+	}
+	if err := gui.NpmManager.LoadDepNodeChildren(node); err != nil {
+		return err
+	}
+	node.Expanded = true
+
+	gui.refreshDepsView()
+	return nil
+}
+
+// handleCollapseDepNode collapses the node if it's expanded, otherwise it
+// collapses the node's parent and selects that instead
+func (gui *Gui) handleCollapseDepNode(node *commands.DepNode) error {
+	if !node.Expanded {
+		if node.Parent == nil {
+			return nil
+		}
+		node = node.Parent
+	}
+	node.Expanded = false
+
+	for i, visibleNode := range gui.getVisibleDepNodes() {
+		if visibleNode == node {
+			gui.State.Panels.DepTree.SelectedLine = i
+		}
+	}
+
+	gui.refreshDepsView()
+	gui.getDepsView().FocusPoint(0, gui.State.Panels.DepTree.SelectedLine)
+	return gui.handleDepTreeNodeSelect(gui.g, gui.getDepsView())
+}
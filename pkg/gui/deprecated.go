@@ -0,0 +1,36 @@
+package gui
+
+import (
+	"path/filepath"
+
+	"github.com/jesseduffield/lazynpm/pkg/gui/presentation"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// setDepDeprecations tells each dependency whether it's deprecated and which
+// deprecated packages it pulls in
+func (gui *Gui) setDepDeprecations() {
+	rootPath := gui.currentPackage().Path
+	for _, dep := range gui.State.Deps {
+		dep.Deprecated = ""
+		dep.DeprecatedDependencies = nil
+		for _, deprecation := range gui.State.Deprecations {
+			if !utils.IncludesString(deprecation.Via, dep.Name) {
+				continue
+			}
+			if filepath.Join(rootPath, deprecation.Path) == dep.Path {
+				dep.Deprecated = deprecation.Message
+				continue
+			}
+			dep.DeprecatedDependencies = append(dep.DeprecatedDependencies, deprecation)
+		}
+	}
+}
+
+func (gui *Gui) handleShowDeprecations() error {
+	if gui.State.Deprecations == nil {
+		return gui.createErrorPanel("still checking for deprecations, try again in a moment")
+	}
+	gui.printToMain(presentation.DeprecationsOutput(gui.State.Deprecations))
+	return nil
+}
@@ -0,0 +1,112 @@
+package gui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/gui/presentation"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// handleDistTags fetches the package's dist-tags from the registry and lets
+// the user add, move, or remove them
+func (gui *Gui) handleDistTags(pkg *commands.Package) error {
+	if pkg.Config.Private {
+		return gui.createErrorPanel("private packages are not published so they have no dist-tags")
+	}
+
+	return gui.WithWaitingStatus("fetching dist-tags", func() error {
+		registryPackage, err := gui.NpmManager.GetRegistryPackage(pkg, pkg.Config.Name)
+		if err != nil {
+			return err
+		}
+		gui.g.Update(func(*gocui.Gui) error {
+			return gui.createDistTagsMenu(pkg, registryPackage)
+		})
+		return nil
+	})
+}
+
+func (gui *Gui) createDistTagsMenu(pkg *commands.Package, registryPackage *commands.RegistryPackage) error {
+	tags := make([]string, 0, len(registryPackage.DistTags))
+	for tag := range registryPackage.DistTags {
+		tags = append(tags, tag)
+	}
+	// latest first, given it's what everybody gets by default
+	sort.Slice(tags, func(i, j int) bool {
+		if (tags[i] == "latest") != (tags[j] == "latest") {
+			return tags[i] == "latest"
+		}
+		return tags[i] < tags[j]
+	})
+
+	menuItems := make([]*menuItem, 0, len(tags)+1)
+	for _, tag := range tags {
+		tag := tag
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: []string{utils.ColoredString(tag, color.FgCyan), registryPackage.DistTags[tag]},
+			onPress: func() error {
+				return gui.createDistTagMenu(pkg, registryPackage, tag)
+			},
+		})
+	}
+	menuItems = append(menuItems, &menuItem{
+		displayStrings: []string{"add tag", "point a new tag at a version"},
+		onPress: func() error {
+			return gui.createPromptPanel(gui.getPackagesView(), "Tag name", "", func(tag string) error {
+				return gui.promptDistTagVersion(pkg, registryPackage, strings.TrimSpace(tag))
+			})
+		},
+	})
+
+	return gui.createMenu(fmt.Sprintf("dist-tags of %s", pkg.Config.Name), menuItems, createMenuOptions{showCancel: true})
+}
+
+func (gui *Gui) createDistTagMenu(pkg *commands.Package, registryPackage *commands.RegistryPackage, tag string) error {
+	menuItems := []*menuItem{
+		{
+			displayStrings: []string{"move", "point the tag at another version"},
+			onPress: func() error {
+				return gui.promptDistTagVersion(pkg, registryPackage, tag)
+			},
+		},
+		{
+			displayStrings: []string{"remove", utils.ColoredString(pkg.PackageManager.DistTagRemove(pkg.Config.Name, tag), color.FgYellow)},
+			onPress: func() error {
+				return gui.confirmDistTagChange(pkg, registryPackage, commands.DistTagChange{Tag: tag})
+			},
+		},
+	}
+
+	return gui.createMenu(fmt.Sprintf("%s (%s)", tag, registryPackage.DistTags[tag]), menuItems, createMenuOptions{showCancel: true})
+}
+
+// promptDistTagVersion defaults to the package's current version, given
+// that's usually the one we've just published
+func (gui *Gui) promptDistTagVersion(pkg *commands.Package, registryPackage *commands.RegistryPackage, tag string) error {
+	return gui.createPromptPanel(gui.getPackagesView(), fmt.Sprintf("Version to tag as %s", tag), pkg.Config.Version, func(version string) error {
+		change := commands.DistTagChange{Tag: strings.TrimSpace(tag), Version: strings.TrimSpace(version)}
+		return gui.confirmDistTagChange(pkg, registryPackage, change)
+	})
+}
+
+func (gui *Gui) confirmDistTagChange(pkg *commands.Package, registryPackage *commands.RegistryPackage, change commands.DistTagChange) error {
+	if err := change.Validate(registryPackage); err != nil {
+		return gui.createErrorPanel(err.Error())
+	}
+
+	cmdStr := change.Command(pkg.PackageManager, pkg.Config.Name)
+	return gui.createConfirmationPanel(createConfirmationPanelOpts{
+		returnToView:       gui.getPackagesView(),
+		returnFocusOnClose: true,
+		title:              "Change dist-tags",
+		prompt:             presentation.DistTagChangeOutput(registryPackage.DistTags, change.Apply(registryPackage.DistTags), cmdStr),
+		handleConfirm: func() error {
+			return gui.newMainCommand(cmdStr, pkg.ID(), newMainCommandOptions{})
+		},
+	})
+}
@@ -0,0 +1,98 @@
+package gui
+
+import (
+	"github.com/fatih/color"
+	"github.com/go-errors/errors"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/gui/presentation"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// like the dependency tree, the duplicates report is shown in the deps view
+// under its own context
+
+const DUPLICATES_CONTEXT = "duplicates"
+
+func (gui *Gui) inDuplicates() bool {
+	return gui.State.Duplicates != nil
+}
+
+func (gui *Gui) getSelectedDuplicate() *commands.DuplicatePackage {
+	duplicates := gui.State.Duplicates
+	if len(duplicates) == 0 {
+		return nil
+	}
+	return duplicates[gui.State.Panels.Duplicates.SelectedLine]
+}
+
+func (gui *Gui) handleDuplicateSelect(g *gocui.Gui, v *gocui.View) error {
+	duplicate := gui.getSelectedDuplicate()
+	if duplicate == nil {
+		gui.renderString("secondary", "no packages are installed at more than one version")
+		gui.activateContextView(gui.dedupeContextID())
+		return nil
+	}
+
+	gui.renderString("secondary", presentation.DuplicateSummary(duplicate))
+	gui.activateContextView(gui.dedupeContextID())
+	return nil
+}
+
+// dedupeContextID is the key of the command view for deduping the current package
+func (gui *Gui) dedupeContextID() string {
+	return "dedupe:" + gui.currentPackage().Path
+}
+
+func (gui *Gui) handleOpenDuplicates() error {
+	pkg := gui.currentPackage()
+	return gui.WithWaitingStatus("reading node_modules", func() error {
+		duplicates := gui.NpmManager.BuildDependentsIndex(pkg).Duplicates()
+
+		gui.g.Update(func(*gocui.Gui) error {
+			if gui.currentPackage() != pkg || gui.inDepTree() {
+				return nil
+			}
+			gui.State.Duplicates = duplicates
+			gui.refreshSelectedLine(&gui.State.Panels.Duplicates.SelectedLine, len(duplicates))
+
+			depsView := gui.getDepsView()
+			depsView.Context = DUPLICATES_CONTEXT
+			depsView.Title = gui.Tr.SLocalize("DuplicatesTitle")
+
+			gui.refreshDepsView()
+			return gui.handleDuplicateSelect(gui.g, depsView)
+		})
+		return nil
+	})
+}
+
+func (gui *Gui) handleExitDuplicates() error {
+	gui.State.Duplicates = nil
+
+	depsView := gui.getDepsView()
+	depsView.Context = ""
+	depsView.Title = gui.Tr.SLocalize("DepsTitle")
+
+	gui.refreshDepsView()
+	return gui.handleDepSelect(gui.g, depsView)
+}
+
+func (gui *Gui) handleDedupe() error {
+	pkg := gui.currentPackage()
+	cmdStr := pkg.PackageManager.Dedupe(gui.cmdOpts(pkg))
+	if cmdStr == "" {
+		return gui.surfaceError(errors.New(pkg.PackageManager.Name() + " does not support deduping"))
+	}
+
+	gui.renderString("secondary", utils.ColoredString(cmdStr, color.FgYellow))
+	return gui.newMainCommand(cmdStr, gui.dedupeContextID(), newMainCommandOptions{
+		onSuccess: func() {
+			gui.invalidateBackgroundChecks()
+			// rescanning so that the report reflects what's now installed
+			if gui.inDuplicates() {
+				_ = gui.handleOpenDuplicates()
+			}
+		},
+	})
+}
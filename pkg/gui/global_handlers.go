@@ -64,6 +64,10 @@ func (gui *Gui) currentContextViewID() string {
 		return gui.selectedScriptID()
 	case "tarballs":
 		return gui.selectedTarballID()
+	case "vulnerabilities":
+		return gui.auditContextID()
+	case "global":
+		return gui.selectedGlobalPackageID()
 	}
 	return ""
 }
@@ -99,6 +103,8 @@ func (gui *Gui) scrollDownConfirmationPanel(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) handleRefresh(g *gocui.Gui, v *gocui.View) error {
+	gui.invalidateBackgroundChecks()
+	gui.invalidateGlobalPackages()
 	return gui.refreshPackages()
 }
 
@@ -0,0 +1,164 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/gui/presentation"
+)
+
+// list panel functions
+
+func (gui *Gui) getSelectedGlobalPackage() *commands.GlobalPackage {
+	pkgs := gui.State.GlobalPackages
+	if len(pkgs) == 0 {
+		return nil
+	}
+	return pkgs[gui.State.Panels.Global.SelectedLine]
+}
+
+func (gui *Gui) handleGlobalPackageSelect(g *gocui.Gui, v *gocui.View) error {
+	if !gui.showGlobalView() {
+		// we hide the global view when there's nothing installed globally
+		if err := gui.switchFocus(nil, gui.getScriptsView()); err != nil {
+			return err
+		}
+	}
+
+	pkg := gui.getSelectedGlobalPackage()
+	if pkg == nil {
+		return nil
+	}
+	gui.renderString("secondary", presentation.GlobalPackageSummary(pkg))
+	gui.activateContextView(pkg.ID())
+	return nil
+}
+
+func (gui *Gui) selectedGlobalPackageID() string {
+	pkg := gui.getSelectedGlobalPackage()
+	if pkg == nil {
+		return ""
+	}
+
+	return pkg.ID()
+}
+
+func (gui *Gui) showGlobalView() bool {
+	return len(gui.State.GlobalPackages) > 0
+}
+
+// refreshStateGlobalPackages reads npm's global root, checking what's outdated
+// in the background if we haven't already
+func (gui *Gui) refreshStateGlobalPackages() error {
+	var err error
+	gui.State.GlobalPackages, err = gui.NpmManager.GetGlobalPackages()
+	if err != nil {
+		return err
+	}
+	gui.State.GlobalPackagesLoaded = true
+
+	if !gui.State.GlobalOutdatedChecked {
+		gui.refreshGlobalOutdated()
+	}
+	for _, pkg := range gui.State.GlobalPackages {
+		pkg.Outdated = gui.State.GlobalOutdated[pkg.Name]
+	}
+
+	gui.refreshSelectedLine(&gui.State.Panels.Global.SelectedLine, len(gui.State.GlobalPackages))
+	return nil
+}
+
+// refreshGlobalOutdated asks npm which global packages are outdated. This hits
+// the registry so we do it in the background
+func (gui *Gui) refreshGlobalOutdated() {
+	gui.State.GlobalOutdatedChecked = true
+	gui.State.GlobalOutdated = nil
+
+	_ = gui.WithWaitingStatus("checking for outdated global packages", func() error {
+		outdated, err := gui.NpmManager.GetGlobalOutdated()
+		if err != nil {
+			return err
+		}
+
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.State.GlobalOutdated = outdated
+			return gui.refreshPackages()
+		})
+		return nil
+	})
+}
+
+// invalidateGlobalPackages means we'll reread npm's global root and check for
+// outdated global packages again on the next refresh
+func (gui *Gui) invalidateGlobalPackages() {
+	gui.State.GlobalPackagesLoaded = false
+	gui.State.GlobalOutdatedChecked = false
+}
+
+func globalPackageNames(pkgs []*commands.GlobalPackage) []string {
+	names := make([]string, len(pkgs))
+	for i, pkg := range pkgs {
+		names[i] = pkg.Name
+	}
+	return names
+}
+
+// like handleAddDependency, we store the command against whichever global
+// package was selected when it was initiated
+func (gui *Gui) handleGlobalInstall(pkg *commands.GlobalPackage) error {
+	return gui.createPromptPanel(gui.getGlobalView(), "Install globally (e.g. name or name@version):", "", func(input string) error {
+		input = strings.TrimSpace(input)
+		if input == "" {
+			return nil
+		}
+		cmdStr := (&commands.Npm{}).GlobalInstall(input)
+		return gui.newMainCommand(cmdStr, pkg.ID(), newMainCommandOptions{onSuccess: gui.invalidateGlobalPackages})
+	})
+}
+
+// linked packages are left alone given there's nothing in the registry to
+// update them from
+func (gui *Gui) handleGlobalUpdate(pkgs []*commands.GlobalPackage) error {
+	toUpdate := []*commands.GlobalPackage{}
+	for _, pkg := range pkgs {
+		if !pkg.Linked() {
+			toUpdate = append(toUpdate, pkg)
+		}
+	}
+	if len(toUpdate) == 0 {
+		return gui.createErrorPanel("linked packages can't be updated from the registry")
+	}
+
+	cmdStr := (&commands.Npm{}).GlobalUpdate(globalPackageNames(toUpdate)...)
+	gui.globalListView().clearSelection()
+	return gui.newMainCommand(cmdStr, toUpdate[0].ID(), newMainCommandOptions{onSuccess: gui.invalidateGlobalPackages})
+}
+
+func (gui *Gui) handleGlobalUninstall(pkgs []*commands.GlobalPackage) error {
+	names := globalPackageNames(pkgs)
+	cmdStr := (&commands.Npm{}).GlobalUninstall(names...)
+
+	return gui.createConfirmationPanel(createConfirmationPanelOpts{
+		returnToView:       gui.getGlobalView(),
+		returnFocusOnClose: true,
+		title:              "Uninstall global package",
+		prompt:             fmt.Sprintf("are you sure you want to uninstall %s globally?", strings.Join(names, ", ")),
+		handleConfirm: func() error {
+			gui.globalListView().clearSelection()
+			return gui.newMainCommand(cmdStr, pkgs[0].ID(), newMainCommandOptions{onSuccess: gui.invalidateGlobalPackages})
+		},
+	})
+}
+
+func (gui *Gui) wrappedGlobalPackageHandler(f func(*commands.GlobalPackage) error) func(*gocui.Gui, *gocui.View) error {
+	return gui.wrappedHandler(func() error {
+		pkg := gui.getSelectedGlobalPackage()
+		if pkg == nil {
+			return nil
+		}
+
+		return gui.finalStep(f(pkg))
+	})
+}
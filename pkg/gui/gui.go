@@ -88,10 +88,20 @@ type Gui struct {
 
 type packagesPanelState struct {
 	SelectedLine int
+	Selection    *listSelection
 }
 
 type depsPanelState struct {
 	SelectedLine int
+	Selection    *listSelection
+}
+
+type depTreePanelState struct {
+	SelectedLine int
+}
+
+type duplicatesPanelState struct {
+	SelectedLine int
 }
 
 type scriptsPanelState struct {
@@ -100,6 +110,16 @@ type scriptsPanelState struct {
 
 type tarballsPanelState struct {
 	SelectedLine int
+	Selection    *listSelection
+}
+
+type globalPanelState struct {
+	SelectedLine int
+	Selection    *listSelection
+}
+
+type vulnerabilitiesPanelState struct {
+	SelectedLine int
 }
 
 type menuPanelState struct {
@@ -108,11 +128,15 @@ type menuPanelState struct {
 }
 
 type panelStates struct {
-	Packages *packagesPanelState
-	Deps     *depsPanelState
-	Scripts  *scriptsPanelState
-	Tarballs *tarballsPanelState
-	Menu     *menuPanelState
+	Packages        *packagesPanelState
+	Deps            *depsPanelState
+	DepTree         *depTreePanelState
+	Duplicates      *duplicatesPanelState
+	Scripts         *scriptsPanelState
+	Tarballs        *tarballsPanelState
+	Global          *globalPanelState
+	Vulnerabilities *vulnerabilitiesPanelState
+	Menu            *menuPanelState
 }
 
 type searchingState struct {
@@ -140,6 +164,60 @@ type guiState struct {
 	OldInformation    string
 	CurrentPackageIdx int
 	CommandViewMap    commands.CommandViewMap
+	// Outdated maps the current package's dependency names to what the
+	// package manager says about them being outdated
+	Outdated map[string]*commands.OutdatedInfo
+	// OutdatedPackagePath is the path of the package we've fetched Outdated for
+	OutdatedPackagePath string
+	// Advisories are the vulnerabilities that the package manager found in the current package
+	Advisories []*commands.Advisory
+	// AuditPackagePath is the path of the package we've fetched Advisories for
+	AuditPackagePath string
+	// DiskUsage maps the current package's dependency names to how much disk
+	// they take up
+	DiskUsage map[string]*commands.DiskUsage
+	// EngineVersions are the versions of node and npm we're running, once we know them
+	EngineVersions *commands.EngineVersions
+	// EngineViolations are the packages, including the current package and
+	// everything in its node_modules, whose engines rule out EngineVersions
+	EngineViolations []*commands.EngineViolation
+	// PeerIssues are the unmet or mismatched peer dependencies of the packages
+	// in the current package's node_modules
+	PeerIssues []*commands.PeerIssue
+	// Licenses is the license report for the current package's installed dependencies
+	Licenses *commands.LicenseReport
+	// Deprecations are the deprecated packages installed in the current
+	// package's node_modules, once we've looked
+	Deprecations []*commands.DeprecatedPackage
+	// NodeModulesPackagePath is the path of the package whose node_modules
+	// we've checked for DiskUsage, EngineViolations, PeerIssues, Licenses, and
+	// Deprecations
+	NodeModulesPackagePath string
+	// SortDepsBySize is true when the deps view is sorted by disk usage rather
+	// than by kind and name
+	SortDepsBySize bool
+	// DepTree is the root of the dependency tree we're browsing in the deps
+	// view, if any
+	DepTree *commands.DepNode
+	// Duplicates is the report of packages installed at more than one version
+	// that we're showing in the deps view, if any
+	Duplicates []*commands.DuplicatePackage
+	// ComparisonTarball is the tarball the user has picked to compare with
+	// another tarball, if any
+	ComparisonTarball *commands.Tarball
+	// HashingTarballs is true while we're hashing tarballs in the background
+	HashingTarballs bool
+	// GlobalPackages are the packages installed in npm's global root
+	GlobalPackages []*commands.GlobalPackage
+	// GlobalPackagesLoaded is false until we've read GlobalPackages, and again
+	// after anything is installed globally
+	GlobalPackagesLoaded bool
+	// GlobalOutdated maps global package names to what npm says about them
+	// being outdated
+	GlobalOutdated map[string]*commands.OutdatedInfo
+	// GlobalOutdatedChecked is false until we've asked npm about GlobalOutdated,
+	// and again after anything is installed globally
+	GlobalOutdatedChecked bool
 }
 
 func (gui *Gui) resetState() {
@@ -147,11 +225,15 @@ func (gui *Gui) resetState() {
 		Packages:     make([]*commands.Package, 0),
 		PreviousView: "packages",
 		Panels: &panelStates{
-			Packages: &packagesPanelState{SelectedLine: 0},
-			Deps:     &depsPanelState{SelectedLine: 0},
-			Scripts:  &scriptsPanelState{SelectedLine: 0},
-			Tarballs: &tarballsPanelState{SelectedLine: 0},
-			Menu:     &menuPanelState{SelectedLine: 0},
+			Packages:        &packagesPanelState{SelectedLine: 0, Selection: &listSelection{}},
+			Deps:            &depsPanelState{SelectedLine: 0, Selection: &listSelection{}},
+			DepTree:         &depTreePanelState{SelectedLine: 0},
+			Duplicates:      &duplicatesPanelState{SelectedLine: 0},
+			Scripts:         &scriptsPanelState{SelectedLine: 0},
+			Tarballs:        &tarballsPanelState{SelectedLine: 0, Selection: &listSelection{}},
+			Global:          &globalPanelState{SelectedLine: 0, Selection: &listSelection{}},
+			Vulnerabilities: &vulnerabilitiesPanelState{SelectedLine: 0},
+			Menu:            &menuPanelState{SelectedLine: 0},
 		},
 		Ptmx:           nil,
 		CommandViewMap: commands.CommandViewMap{},
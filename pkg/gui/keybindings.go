@@ -23,6 +23,12 @@ type Binding struct {
 	Alternative string
 }
 
+// pmCommand describes a command that's run with whichever package manager the
+// package uses (npm, yarn, or pnpm)
+func pmCommand(cmd string) string {
+	return utils.ColoredString(fmt.Sprintf("`<pm> %s`", cmd), color.FgYellow)
+}
+
 // GetDisplayStrings returns the display string of a file
 func (b *Binding) GetDisplayStrings(isFocused bool) []string {
 	return []string{GetKeyDisplay(b.Key), b.Description}
@@ -176,7 +182,7 @@ func GetKeyDisplay(key interface{}) string {
 		keyInt = int(key)
 	}
 
-	return string(keyInt)
+	return string(rune(keyInt))
 }
 
 func (gui *Gui) getKey(name string) interface{} {
@@ -343,6 +349,11 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Key:      gui.getKey("universal.return"),
 			Handler:  gui.handleSearchEscape,
 		},
+		{
+			ViewName: "search",
+			Key:      gui.getKey("dependencies.whySearch"),
+			Handler:  gui.handleWhySearch,
+		},
 		{
 			ViewName: "confirmation",
 			Key:      gui.getKey("universal.prevItem"),
@@ -379,23 +390,41 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Handler:     gui.wrappedHandler(gui.handleAddPackage),
 			Description: "add package to list",
 		},
+		{
+			ViewName:    "packages",
+			Key:         gui.getKey("packages.version"),
+			Handler:     gui.wrappedPackageHandler(gui.handleVersionBump),
+			Description: "bump version",
+		},
+		{
+			ViewName:    "packages",
+			Key:         gui.getKey("packages.packList"),
+			Handler:     gui.wrappedPackageHandler(gui.handleShowPackList),
+			Description: "list files that would be published",
+		},
+		{
+			ViewName:    "packages",
+			Key:         gui.getKey("packages.distTags"),
+			Handler:     gui.wrappedPackageHandler(gui.handleDistTags),
+			Description: "manage dist-tags",
+		},
 		{
 			ViewName:    "packages",
 			Key:         gui.getKey("packages.pack"),
-			Handler:     gui.wrappedPackageHandler(gui.handlePackPackage),
-			Description: fmt.Sprintf("%s package", utils.ColoredString("`npm pack`", color.FgYellow)),
+			Handler:     gui.wrappedPackagesHandler(gui.handlePackPackage),
+			Description: fmt.Sprintf("%s package", pmCommand("pack")),
 		},
 		{
 			ViewName:    "packages",
 			Key:         gui.getKey("packages.link"),
 			Handler:     gui.wrappedHandler(gui.handleLinkPackage),
-			Description: fmt.Sprintf("%s (or unlink if already linked)", utils.ColoredString("`npm link <package>`", color.FgYellow)),
+			Description: fmt.Sprintf("%s (or unlink if already linked)", pmCommand("link <package>")),
 		},
 		{
 			ViewName:    "packages",
 			Key:         gui.getKey("packages.globalLink"),
 			Handler:     gui.wrappedPackageHandler(gui.handleGlobalLinkPackage),
-			Description: fmt.Sprintf("%s (i.e. globally link) (or unlink if already linked)", utils.ColoredString("`npm link`", color.FgYellow)),
+			Description: fmt.Sprintf("%s (i.e. globally link) (or unlink if already linked)", pmCommand("link")),
 		},
 		{
 			ViewName:    "packages",
@@ -406,14 +435,14 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 		{
 			ViewName:    "packages",
 			Key:         gui.getKey("universal.install"),
-			Handler:     gui.wrappedPackageHandler(gui.handleInstall),
-			Description: fmt.Sprintf("%s package", utils.ColoredString("`npm install`", color.FgYellow)),
+			Handler:     gui.wrappedPackagesHandler(gui.handleInstall),
+			Description: fmt.Sprintf("%s package", pmCommand("install")),
 		},
 		{
 			ViewName:    "packages",
 			Key:         gui.getKey("packages.build"),
-			Handler:     gui.wrappedPackageHandler(gui.handleBuild),
-			Description: fmt.Sprintf("%s package", utils.ColoredString("`npm run build`", color.FgYellow)),
+			Handler:     gui.wrappedPackagesHandler(gui.handleBuild),
+			Description: fmt.Sprintf("%s package", pmCommand("run build")),
 		},
 		{
 			ViewName:    "packages",
@@ -425,13 +454,19 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			ViewName:    "packages",
 			Key:         gui.getKey("universal.update"),
 			Handler:     gui.wrappedPackageHandler(gui.handlePackageUpdate),
-			Description: fmt.Sprintf("%s package", utils.ColoredString("`npm update`", color.FgYellow)),
+			Description: fmt.Sprintf("%s package", pmCommand("update")),
+		},
+		{
+			ViewName:    "packages",
+			Key:         gui.getKey("packages.setPackageManager"),
+			Handler:     gui.wrappedPackageHandler(gui.handleSetPackageManager),
+			Description: "set package manager (npm/yarn/pnpm)",
 		},
 		{
 			ViewName:    "scripts",
 			Key:         gui.getKey("universal.select"),
 			Handler:     gui.wrappedScriptHandler(gui.handleRunScript),
-			Description: fmt.Sprintf("%s script", utils.ColoredString("`npm run`", color.FgYellow)),
+			Description: fmt.Sprintf("%s script", pmCommand("run")),
 		},
 		{
 			ViewName:    "scripts",
@@ -453,67 +488,231 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 		},
 		{
 			ViewName:    "deps",
+			Contexts:    []string{""},
+			Key:         gui.getKey("universal.select"),
+			Handler:     gui.wrappedDependencyHandler(gui.handleOpenDepTree),
+			Description: "view dependency tree",
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{DEP_TREE_CONTEXT},
+			Key:         gui.getKey("universal.select"),
+			Handler:     gui.wrappedDepNodeHandler(gui.handleToggleDepNode),
+			Description: "expand/collapse",
+		},
+		{
+			ViewName: "deps",
+			Contexts: []string{DEP_TREE_CONTEXT},
+			Key:      gui.getKey("universal.nextBlock"),
+			Handler:  gui.wrappedDepNodeHandler(gui.handleExpandDepNode),
+		},
+		{
+			ViewName: "deps",
+			Contexts: []string{DEP_TREE_CONTEXT},
+			Key:      gui.getKey("universal.nextBlock-alt"),
+			Handler:  gui.wrappedDepNodeHandler(gui.handleExpandDepNode),
+		},
+		{
+			ViewName: "deps",
+			Contexts: []string{DEP_TREE_CONTEXT},
+			Key:      gui.getKey("universal.prevBlock"),
+			Handler:  gui.wrappedDepNodeHandler(gui.handleCollapseDepNode),
+		},
+		{
+			ViewName: "deps",
+			Contexts: []string{DEP_TREE_CONTEXT},
+			Key:      gui.getKey("universal.prevBlock-alt"),
+			Handler:  gui.wrappedDepNodeHandler(gui.handleCollapseDepNode),
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{DEP_TREE_CONTEXT},
+			Key:         gui.getKey("universal.return"),
+			Handler:     gui.wrappedHandler(gui.handleExitDepTree),
+			Description: "exit dependency tree",
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{""},
+			Key:         gui.getKey("dependencies.why"),
+			Handler:     gui.wrappedDependencyHandler(gui.handleWhyDep),
+			Description: "why is this installed?",
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{DEP_TREE_CONTEXT},
+			Key:         gui.getKey("dependencies.why"),
+			Handler:     gui.wrappedDepNodeHandler(gui.handleWhyDepNode),
+			Description: "why is this installed?",
+		},
+		{
+			ViewName:    "deps",
+			Key:         gui.getKey("dependencies.whySearch"),
+			Handler:     gui.handleWhySearch,
+			Description: "why is the searched-for package installed?",
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{""},
+			Key:         gui.getKey("dependencies.sortBySize"),
+			Handler:     gui.wrappedHandler(gui.handleToggleSortDepsBySize),
+			Description: "toggle sorting by disk usage",
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{""},
+			Key:         gui.getKey("dependencies.licenses"),
+			Handler:     gui.wrappedHandler(gui.handleShowLicenses),
+			Description: "show license report",
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{""},
+			Key:         gui.getKey("dependencies.deprecations"),
+			Handler:     gui.wrappedHandler(gui.handleShowDeprecations),
+			Description: "show deprecated packages",
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{""},
+			Key:         gui.getKey("dependencies.viewInfo"),
+			Handler:     gui.wrappedDependencyHandler(gui.handleViewDepInfo),
+			Description: "view registry info",
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{""},
+			Key:         gui.getKey("dependencies.viewDuplicates"),
+			Handler:     gui.wrappedHandler(gui.handleOpenDuplicates),
+			Description: "view packages installed at more than one version",
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{DUPLICATES_CONTEXT},
+			Key:         gui.getKey("dependencies.dedupe"),
+			Handler:     gui.wrappedHandler(gui.handleDedupe),
+			Description: pmCommand("dedupe"),
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{DUPLICATES_CONTEXT},
+			Key:         gui.getKey("universal.return"),
+			Handler:     gui.wrappedHandler(gui.handleExitDuplicates),
+			Description: "exit duplicates view",
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{""},
 			Key:         gui.getKey("universal.install"),
 			Handler:     gui.wrappedDependencyHandler(gui.handleDepInstall),
-			Description: fmt.Sprintf("%s dependency", utils.ColoredString("`npm install`", color.FgYellow)),
+			Description: fmt.Sprintf("%s dependency", pmCommand("install")),
 		},
 		{
 			ViewName:    "deps",
+			Contexts:    []string{""},
 			Key:         gui.getKey("universal.openFile"),
 			Handler:     gui.wrappedDependencyHandler(gui.handleOpenDepPackageConfig),
 			Description: "open package.json",
 		},
 		{
 			ViewName:    "deps",
+			Contexts:    []string{""},
 			Key:         gui.getKey("universal.update"),
-			Handler:     gui.wrappedDependencyHandler(gui.handleDepUpdate),
-			Description: fmt.Sprintf("%s dependency", utils.ColoredString("`npm update`", color.FgYellow)),
+			Handler:     gui.wrappedDependenciesHandler(gui.handleDepUpdate),
+			Description: fmt.Sprintf("%s dependency", pmCommand("update")),
 		},
 		{
 			ViewName:    "deps",
+			Contexts:    []string{""},
+			Key:         gui.getKey("dependencies.upgrade"),
+			Handler:     gui.wrappedDependencyHandler(gui.handleDepUpgrade),
+			Description: "update dependency to wanted/latest version",
+		},
+		{
+			ViewName:    "deps",
+			Contexts:    []string{""},
 			Key:         gui.getKey("universal.remove"),
-			Handler:     gui.wrappedDependencyHandler(gui.handleDepUninstall),
-			Description: fmt.Sprintf("%s dependency", utils.ColoredString("`npm uninstall`", color.FgYellow)),
+			Handler:     gui.wrappedDependenciesHandler(gui.handleDepUninstall),
+			Description: fmt.Sprintf("%s dependency", pmCommand("uninstall")),
 		},
 		{
 			ViewName:    "deps",
+			Contexts:    []string{""},
 			Key:         gui.getKey("dependencies.changeType"),
-			Handler:     gui.wrappedDependencyHandler(gui.handleChangeDepType),
+			Handler:     gui.wrappedDependenciesHandler(gui.handleChangeDepType),
 			Description: "change dependency type (prod/dev/optional)",
 		},
 		{
 			ViewName:    "deps",
+			Contexts:    []string{""},
 			Key:         gui.getKey("universal.new"),
 			Handler:     gui.wrappedDependencyHandler(gui.handleAddDependency),
-			Description: fmt.Sprintf("%s new dependency", utils.ColoredString("`npm install`", color.FgYellow)),
+			Description: fmt.Sprintf("%s new dependency", pmCommand("install")),
 		},
 		{
 			ViewName:    "deps",
+			Contexts:    []string{""},
 			Key:         gui.getKey("universal.edit"),
-			Handler:     gui.wrappedDependencyHandler(gui.handleEditDepConstraint),
+			Handler:     gui.wrappedDependenciesHandler(gui.handleEditDepConstraints),
 			Description: "edit dependency constraint",
 		},
 		{
 			ViewName:    "tarballs",
 			Key:         gui.getKey("universal.remove"),
-			Handler:     gui.wrappedTarballHandler(gui.handleDeleteTarball),
+			Handler:     gui.wrappedTarballsHandler(gui.handleDeleteTarballs),
 			Description: "delete tarball",
 		},
 		{
 			ViewName:    "tarballs",
 			Key:         gui.getKey("universal.install"),
 			Handler:     gui.wrappedTarballHandler(gui.handleInstallTarball),
-			Description: fmt.Sprintf("%s tarball", utils.ColoredString("`npm install`", color.FgYellow)),
+			Description: fmt.Sprintf("%s tarball", pmCommand("install")),
 		},
 		{
 			ViewName:    "tarballs",
 			Key:         gui.getKey("packages.publish"),
 			Handler:     gui.wrappedTarballHandler(gui.handlePublishTarball),
-			Description: fmt.Sprintf("%s tarball", utils.ColoredString("`npm publish`", color.FgYellow)),
+			Description: fmt.Sprintf("%s tarball", pmCommand("publish")),
+		},
+		{
+			ViewName:    "tarballs",
+			Key:         gui.getKey("tarballs.compare"),
+			Handler:     gui.wrappedTarballHandler(gui.handleCompareTarball),
+			Description: "compare with another tarball or the source package",
+		},
+		{
+			ViewName:    "vulnerabilities",
+			Key:         gui.getKey("vulnerabilities.fix"),
+			Handler:     gui.wrappedHandler(gui.handleAuditFix),
+			Description: pmCommand("audit fix"),
+		},
+		{
+			ViewName:    "vulnerabilities",
+			Key:         gui.getKey("vulnerabilities.forceFix"),
+			Handler:     gui.wrappedHandler(gui.handleAuditForceFix),
+			Description: pmCommand("audit fix --force"),
+		},
+		{
+			ViewName:    "global",
+			Key:         gui.getKey("universal.new"),
+			Handler:     gui.wrappedGlobalPackageHandler(gui.handleGlobalInstall),
+			Description: fmt.Sprintf("%s a package", utils.ColoredString("`npm install -g`", color.FgYellow)),
+		},
+		{
+			ViewName:    "global",
+			Key:         gui.getKey("universal.update"),
+			Handler:     gui.wrappedGlobalPackagesHandler(gui.handleGlobalUpdate),
+			Description: utils.ColoredString("`npm update -g`", color.FgYellow),
+		},
+		{
+			ViewName:    "global",
+			Key:         gui.getKey("universal.remove"),
+			Handler:     gui.wrappedGlobalPackagesHandler(gui.handleGlobalUninstall),
+			Description: utils.ColoredString("`npm uninstall -g`", color.FgYellow),
 		},
 	}
 
-	for _, viewName := range []string{"status", "packages", "deps", "scripts", "tarballs", "menu"} {
+	for _, viewName := range []string{"status", "packages", "deps", "scripts", "tarballs", "vulnerabilities", "global", "menu"} {
 		bindings = append(bindings, []*Binding{
 			{ViewName: viewName, Key: gui.getKey("universal.togglePanel"), Handler: gui.nextView},
 			{ViewName: viewName, Key: gui.getKey("universal.prevBlock"), Handler: gui.previousView},
@@ -524,7 +723,7 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 	}
 
 	// Appends keybindings to jump to a particular sideView using numbers
-	for i, viewName := range []string{"status", "packages", "deps", "scripts", "tarballs"} {
+	for i, viewName := range []string{"status", "packages", "deps", "scripts", "tarballs", "vulnerabilities", "global"} {
 		bindings = append(bindings, &Binding{ViewName: "", Key: rune(i+1) + '0', Handler: gui.goToSideView(viewName)})
 		bindings = append(bindings, &Binding{ViewName: viewName, Key: gui.getKey("universal.goInto"), Handler: gui.wrappedHandler(gui.enterMainView)})
 	}
@@ -556,6 +755,33 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 				Description: gui.Tr.SLocalize("startSearch"),
 			},
 		}...)
+
+		if listView.getItemIDs == nil {
+			continue
+		}
+		bindings = append(bindings, []*Binding{
+			{
+				ViewName:    listView.viewName,
+				Contexts:    []string{listView.context},
+				Key:         gui.getKey("universal.toggleSelected"),
+				Handler:     listView.handleToggleSelected,
+				Description: gui.Tr.SLocalize("toggleSelected"),
+			},
+			{
+				ViewName:    listView.viewName,
+				Contexts:    []string{listView.context},
+				Key:         gui.getKey("universal.toggleRangeSelect"),
+				Handler:     listView.handleToggleRangeSelect,
+				Description: gui.Tr.SLocalize("toggleRangeSelect"),
+			},
+			{
+				ViewName:    listView.viewName,
+				Contexts:    []string{listView.context},
+				Key:         gui.getKey("universal.return"),
+				Handler:     listView.handleClearSelection,
+				Description: gui.Tr.SLocalize("clearSelection"),
+			},
+		}...)
 	}
 
 	return bindings
@@ -46,6 +46,10 @@ func (gui *Gui) onFocusLost(v *gocui.View, newView *gocui.View) error {
 			return err
 		}
 	}
+	if v.Name() == "vulnerabilities" {
+		// no longer highlighting the dependencies affected by the selected advisory
+		gui.refreshDepsView()
+	}
 	gui.Log.Info(v.Name() + " focus lost")
 	return nil
 }
@@ -65,37 +69,44 @@ func (gui *Gui) getViewHeights() map[string]int {
 
 	if gui.State.ScreenMode == SCREEN_FULL || gui.State.ScreenMode == SCREEN_HALF {
 		vHeights := map[string]int{
-			"status":   0,
-			"packages": 0,
-			"deps":     0,
-			"scripts":  0,
-			"tarballs": 0,
-			"options":  0,
+			"status":          0,
+			"packages":        0,
+			"deps":            0,
+			"scripts":         0,
+			"tarballs":        0,
+			"vulnerabilities": 0,
+			"global":          0,
+			"options":         0,
 		}
 		vHeights[currentCyclebleView] = height - 1
 		return vHeights
 	}
 
-	mainSideViewCount := 3
+	// the side views beneath the status view, some of which are hidden when empty
+	sideViews := []string{"packages", "deps", "scripts"}
 	if gui.showTarballsView() {
-		mainSideViewCount = 4
+		sideViews = append(sideViews, "tarballs")
+	}
+	if gui.showVulnerabilitiesView() {
+		sideViews = append(sideViews, "vulnerabilities")
+	}
+	if gui.showGlobalView() {
+		sideViews = append(sideViews, "global")
 	}
+	mainSideViewCount := len(sideViews)
 
 	usableSpace := height - 4
 	extraSpace := usableSpace - (usableSpace/mainSideViewCount)*mainSideViewCount
 
 	if height >= 28 {
 		vHeights := map[string]int{
-			"status":   3,
-			"packages": (usableSpace / mainSideViewCount) + extraSpace,
-			"deps":     usableSpace / mainSideViewCount,
-			"scripts":  usableSpace / mainSideViewCount,
-			"tarballs": usableSpace / mainSideViewCount,
-			"options":  1,
+			"status":  3,
+			"options": 1,
 		}
-		if gui.showTarballsView() {
-			vHeights["tarballs"] = usableSpace / mainSideViewCount
+		for _, viewName := range sideViews {
+			vHeights[viewName] = usableSpace / mainSideViewCount
 		}
+		vHeights["packages"] += extraSpace
 		return vHeights
 	}
 
@@ -104,14 +115,11 @@ func (gui *Gui) getViewHeights() map[string]int {
 		defaultHeight = 1
 	}
 	vHeights := map[string]int{
-		"status":   defaultHeight,
-		"packages": defaultHeight,
-		"deps":     defaultHeight,
-		"scripts":  defaultHeight,
-		"options":  defaultHeight,
+		"status":  defaultHeight,
+		"options": defaultHeight,
 	}
-	if gui.showTarballsView() {
-		vHeights["tarballs"] = defaultHeight
+	for _, viewName := range sideViews {
+		vHeights[viewName] = defaultHeight
 	}
 	vHeights[currentCyclebleView] = height - defaultHeight*mainSideViewCount - 1
 
@@ -281,6 +289,38 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 	}
 	tarballsView.Visible = gui.showTarballsView()
 
+	// the vulnerabilities view goes beneath whichever view is the last one showing
+	aboveVulnerabilitiesView := "scripts"
+	if gui.showTarballsView() {
+		aboveVulnerabilitiesView = "tarballs"
+	}
+	vulnerabilitiesView, err := g.SetViewBeneath("vulnerabilities", aboveVulnerabilitiesView, vHeights["vulnerabilities"])
+	if err != nil {
+		if err.Error() != "unknown view" {
+			return err
+		}
+		vulnerabilitiesView.Title = gui.Tr.SLocalize("VulnerabilitiesTitle")
+		vulnerabilitiesView.FgColor = textColor
+		vulnerabilitiesView.ContainsList = true
+	}
+	vulnerabilitiesView.Visible = gui.showVulnerabilitiesView()
+
+	// global packages have nothing to do with the current package so they go last
+	aboveGlobalView := aboveVulnerabilitiesView
+	if gui.showVulnerabilitiesView() {
+		aboveGlobalView = "vulnerabilities"
+	}
+	globalView, err := g.SetViewBeneath("global", aboveGlobalView, vHeights["global"])
+	if err != nil {
+		if err.Error() != "unknown view" {
+			return err
+		}
+		globalView.Title = gui.Tr.SLocalize("GlobalTitle")
+		globalView.FgColor = textColor
+		globalView.ContainsList = true
+	}
+	globalView.Visible = gui.showGlobalView()
+
 	if v, err := g.SetView("options", appStatusOptionsBoundary-1, height-2, optionsVersionBoundary-1, height, 0); err != nil {
 		if err.Error() != "unknown view" {
 			return err
@@ -372,8 +412,12 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 	listViewStates := []listViewState{
 		{view: packagesView, context: "", selectedLine: gui.State.Panels.Packages.SelectedLine, lineCount: len(gui.State.Packages), listView: gui.packagesListView()},
 		{view: depsView, context: "", selectedLine: gui.State.Panels.Deps.SelectedLine, lineCount: len(gui.State.Deps), listView: gui.depsListView()},
+		{view: depsView, context: DEP_TREE_CONTEXT, selectedLine: gui.State.Panels.DepTree.SelectedLine, lineCount: len(gui.getVisibleDepNodes()), listView: gui.depTreeListView()},
+		{view: depsView, context: DUPLICATES_CONTEXT, selectedLine: gui.State.Panels.Duplicates.SelectedLine, lineCount: len(gui.State.Duplicates), listView: gui.duplicatesListView()},
 		{view: scriptsView, context: "", selectedLine: gui.State.Panels.Scripts.SelectedLine, lineCount: len(gui.getScripts()), listView: gui.scriptsListView()},
 		{view: tarballsView, context: "", selectedLine: gui.State.Panels.Tarballs.SelectedLine, lineCount: len(gui.State.Tarballs), listView: gui.tarballsListView()},
+		{view: vulnerabilitiesView, context: "", selectedLine: gui.State.Panels.Vulnerabilities.SelectedLine, lineCount: len(gui.State.Advisories), listView: gui.vulnerabilitiesListView()},
+		{view: globalView, context: "", selectedLine: gui.State.Panels.Global.SelectedLine, lineCount: len(gui.State.GlobalPackages), listView: gui.globalListView()},
 	}
 
 	// menu view might not exist so we check to be safe
@@ -0,0 +1,24 @@
+package gui
+
+import (
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/gui/presentation"
+)
+
+// licensePolicy is the allow/deny list from the user's config
+func (gui *Gui) licensePolicy() *commands.LicensePolicy {
+	userConfig := gui.Config.GetUserConfig()
+	return &commands.LicensePolicy{
+		Allow:    userConfig.GetStringSlice("licenses.allow"),
+		Deny:     userConfig.GetStringSlice("licenses.deny"),
+		CheckDev: userConfig.GetBool("licenses.checkDevDependencies"),
+	}
+}
+
+func (gui *Gui) handleShowLicenses() error {
+	if gui.State.Licenses == nil {
+		return gui.createErrorPanel("still checking licenses, try again in a moment")
+	}
+	gui.printToMain(presentation.LicenseReportOutput(gui.State.Licenses))
+	return nil
+}
@@ -12,6 +12,10 @@ type listView struct {
 	handleClickSelectedItem func(g *gocui.Gui, v *gocui.View) error
 	gui                     *Gui
 	rendersToMainView       bool
+	// getItemIDs and getSelection are nil for views where you can't select
+	// several items at once
+	getItemIDs   func() []string
+	getSelection func() *listSelection
 }
 
 func (lv *listView) handlePrevLine(g *gocui.Gui, v *gocui.View) error {
@@ -34,6 +38,7 @@ func (lv *listView) handleLineChange(change int) error {
 
 	lv.gui.changeSelectedLine(lv.getSelectedLineIdxPtr(), lv.getItemsLength(), change)
 	view.FocusPoint(0, *lv.getSelectedLineIdxPtr())
+	lv.refreshRangeSelection()
 
 	if lv.rendersToMainView {
 		if err := lv.gui.resetOrigin(lv.gui.getMainView()); err != nil {
@@ -103,6 +108,7 @@ func (lv *listView) handleClick(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	*selectedLineIdxPtr = newSelectedLineIdx
+	lv.refreshRangeSelection()
 
 	prevViewName := lv.gui.currentViewName()
 	if prevSelectedLineIdx == newSelectedLineIdx && prevViewName == lv.viewName && lv.handleClickSelectedItem != nil {
@@ -150,6 +156,14 @@ func (gui *Gui) packagesListView() *listView {
 		handleItemSelect:      gui.handlePackageSelect,
 		gui:                   gui,
 		rendersToMainView:     true,
+		getItemIDs: func() []string {
+			ids := make([]string, len(gui.State.Packages))
+			for i, pkg := range gui.State.Packages {
+				ids[i] = pkg.ID()
+			}
+			return ids
+		},
+		getSelection: func() *listSelection { return gui.State.Panels.Packages.Selection },
 	}
 }
 
@@ -162,6 +176,40 @@ func (gui *Gui) depsListView() *listView {
 		handleItemSelect:      gui.handleDepSelect,
 		gui:                   gui,
 		rendersToMainView:     true,
+		getItemIDs: func() []string {
+			ids := make([]string, len(gui.State.Deps))
+			for i, dep := range gui.State.Deps {
+				ids[i] = dep.ID()
+			}
+			return ids
+		},
+		getSelection: func() *listSelection { return gui.State.Panels.Deps.Selection },
+	}
+}
+
+func (gui *Gui) depTreeListView() *listView {
+	return &listView{
+		viewName:              "deps",
+		context:               DEP_TREE_CONTEXT,
+		getItemsLength:        func() int { return len(gui.getVisibleDepNodes()) },
+		getSelectedLineIdxPtr: func() *int { return &gui.State.Panels.DepTree.SelectedLine },
+		handleFocus:           gui.handleDepTreeNodeSelect,
+		handleItemSelect:      gui.handleDepTreeNodeSelect,
+		gui:                   gui,
+		rendersToMainView:     true,
+	}
+}
+
+func (gui *Gui) duplicatesListView() *listView {
+	return &listView{
+		viewName:              "deps",
+		context:               DUPLICATES_CONTEXT,
+		getItemsLength:        func() int { return len(gui.State.Duplicates) },
+		getSelectedLineIdxPtr: func() *int { return &gui.State.Panels.Duplicates.SelectedLine },
+		handleFocus:           gui.handleDuplicateSelect,
+		handleItemSelect:      gui.handleDuplicateSelect,
+		gui:                   gui,
+		rendersToMainView:     true,
 	}
 }
 
@@ -186,6 +234,46 @@ func (gui *Gui) tarballsListView() *listView {
 		handleItemSelect:      gui.handleTarballSelect,
 		gui:                   gui,
 		rendersToMainView:     true,
+		getItemIDs: func() []string {
+			ids := make([]string, len(gui.State.Tarballs))
+			for i, tarball := range gui.State.Tarballs {
+				ids[i] = tarball.ID()
+			}
+			return ids
+		},
+		getSelection: func() *listSelection { return gui.State.Panels.Tarballs.Selection },
+	}
+}
+
+func (gui *Gui) globalListView() *listView {
+	return &listView{
+		viewName:              "global",
+		getItemsLength:        func() int { return len(gui.State.GlobalPackages) },
+		getSelectedLineIdxPtr: func() *int { return &gui.State.Panels.Global.SelectedLine },
+		handleFocus:           gui.handleGlobalPackageSelect,
+		handleItemSelect:      gui.handleGlobalPackageSelect,
+		gui:                   gui,
+		rendersToMainView:     true,
+		getItemIDs: func() []string {
+			ids := make([]string, len(gui.State.GlobalPackages))
+			for i, pkg := range gui.State.GlobalPackages {
+				ids[i] = pkg.ID()
+			}
+			return ids
+		},
+		getSelection: func() *listSelection { return gui.State.Panels.Global.Selection },
+	}
+}
+
+func (gui *Gui) vulnerabilitiesListView() *listView {
+	return &listView{
+		viewName:              "vulnerabilities",
+		getItemsLength:        func() int { return len(gui.State.Advisories) },
+		getSelectedLineIdxPtr: func() *int { return &gui.State.Panels.Vulnerabilities.SelectedLine },
+		handleFocus:           gui.handleAdvisorySelect,
+		handleItemSelect:      gui.handleAdvisorySelect,
+		gui:                   gui,
+		rendersToMainView:     true,
 	}
 }
 
@@ -194,7 +282,11 @@ func (gui *Gui) getListViews() []*listView {
 		gui.menuListView(),
 		gui.packagesListView(),
 		gui.depsListView(),
+		gui.depTreeListView(),
+		gui.duplicatesListView(),
 		gui.scriptsListView(),
 		gui.tarballsListView(),
+		gui.vulnerabilitiesListView(),
+		gui.globalListView(),
 	}
 }
@@ -0,0 +1,63 @@
+package gui
+
+import "github.com/jesseduffield/gocui"
+
+// refreshNodeModulesChecks works out everything we show about what's installed
+// in the current package's node_modules: disk usage, engine violations, peer
+// issues, licenses, and deprecations. Reading node_modules is slow so we do it
+// once in the background and work everything out from the same index. The
+// registry is checked for new deprecations afterwards so that the rest doesn't
+// have to wait on the network.
+func (gui *Gui) refreshNodeModulesChecks() {
+	pkg := gui.currentPackage()
+	gui.State.NodeModulesPackagePath = pkg.Path
+	gui.State.DiskUsage = nil
+	gui.State.EngineViolations = nil
+	gui.State.PeerIssues = nil
+	gui.State.Licenses = nil
+	gui.State.Deprecations = nil
+
+	_ = gui.WithWaitingStatus("checking node_modules", func() error {
+		index := gui.NpmManager.BuildDependentsIndex(pkg)
+		lockfile, err := gui.NpmManager.GetLockfile(pkg)
+		if err != nil {
+			gui.Log.Error(err)
+		}
+
+		versions := gui.NpmManager.GetEngineVersions()
+		diskUsage := index.DiskUsage()
+		engineViolations := index.EngineViolations(pkg, versions)
+		peerIssues := gui.NpmManager.GetPeerIssues(pkg, index)
+		licenses := index.Licenses(gui.licensePolicy())
+		deprecations := index.Deprecations(lockfile, nil)
+
+		gui.g.Update(func(*gocui.Gui) error {
+			// the user may have switched packages in the meantime
+			if gui.State.NodeModulesPackagePath != pkg.Path {
+				return nil
+			}
+			gui.State.DiskUsage = diskUsage
+			gui.State.EngineVersions = versions
+			gui.State.EngineViolations = engineViolations
+			gui.State.PeerIssues = peerIssues
+			gui.State.Licenses = licenses
+			gui.State.Deprecations = deprecations
+			return gui.refreshPackages()
+		})
+
+		registryMessages := gui.NpmManager.GetRegistryDeprecations(pkg, index)
+		if len(registryMessages) == 0 {
+			return nil
+		}
+		deprecations = index.Deprecations(lockfile, registryMessages)
+
+		gui.g.Update(func(*gocui.Gui) error {
+			if gui.State.NodeModulesPackagePath != pkg.Path {
+				return nil
+			}
+			gui.State.Deprecations = deprecations
+			return gui.refreshPackages()
+		})
+		return nil
+	})
+}
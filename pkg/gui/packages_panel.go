@@ -42,6 +42,10 @@ func (gui *Gui) handlePackageSelect(g *gocui.Gui, v *gocui.View) error {
 	}
 	summary := presentation.PackageSummary(pkg.Config)
 	summary = fmt.Sprintf("%s\nPath: %s", summary, utils.ColoredString(pkg.Path, color.FgCyan))
+	summary = fmt.Sprintf("%s\nPackage manager: %s", summary, utils.ColoredString(pkg.PackageManager.Name(), color.FgMagenta))
+	if pkg.IsWorkspaceMember() {
+		summary = fmt.Sprintf("%s\nWorkspace root: %s", summary, utils.ColoredString(pkg.WorkspaceRootPath, color.FgCyan))
+	}
 	gui.renderString("secondary", summary)
 	gui.activateContextView(pkg.ID())
 	return nil
@@ -67,15 +71,21 @@ func (gui *Gui) refreshPackages() error {
 
 func (gui *Gui) refreshListViews() {
 	displayStrings := presentation.GetPackageListDisplayStrings(gui.State.Packages, gui.linkPathMap(), gui.State.CommandViewMap)
-	gui.renderDisplayStrings(gui.getPackagesView(), displayStrings)
+	gui.renderDisplayStrings(gui.getPackagesView(), markSelected(displayStrings, gui.packagesListView()))
 
 	gui.refreshDepsView()
 
 	displayStrings = presentation.GetScriptListDisplayStrings(gui.getScripts(), gui.State.CommandViewMap)
 	gui.renderDisplayStrings(gui.getScriptsView(), displayStrings)
 
-	displayStrings = presentation.GetTarballListDisplayStrings(gui.State.Tarballs, gui.State.CommandViewMap)
-	gui.renderDisplayStrings(gui.getTarballsView(), displayStrings)
+	displayStrings = presentation.GetTarballListDisplayStrings(gui.State.Tarballs, gui.State.CommandViewMap, gui.State.ComparisonTarball)
+	gui.renderDisplayStrings(gui.getTarballsView(), markSelected(displayStrings, gui.tarballsListView()))
+
+	displayStrings = presentation.GetAdvisoryListDisplayStrings(gui.State.Advisories)
+	gui.renderDisplayStrings(gui.getVulnerabilitiesView(), displayStrings)
+
+	displayStrings = presentation.GetGlobalPackageListDisplayStrings(gui.State.GlobalPackages, gui.State.CommandViewMap)
+	gui.renderDisplayStrings(gui.getGlobalView(), markSelected(displayStrings, gui.globalListView()))
 
 	gui.refreshStatus()
 }
@@ -102,10 +112,46 @@ func (gui *Gui) refreshStatePackages() error {
 		return err
 	}
 
-	gui.State.Tarballs, err = gui.NpmManager.GetTarballs(gui.currentPackage())
+	if gui.State.OutdatedPackagePath != gui.currentPackage().Path {
+		gui.refreshOutdated()
+	}
+	if gui.State.AuditPackagePath != gui.currentPackage().Path {
+		gui.refreshAdvisories()
+	}
+	if gui.State.NodeModulesPackagePath != gui.currentPackage().Path {
+		gui.refreshNodeModulesChecks()
+	}
+	engineViolations := map[string][]*commands.EngineViolation{}
+	for _, violation := range gui.State.EngineViolations {
+		engineViolations[violation.Path] = append(engineViolations[violation.Path], violation)
+	}
+	for _, dep := range gui.State.Deps {
+		dep.Outdated = gui.State.Outdated[dep.Name]
+		dep.DiskUsage = gui.State.DiskUsage[dep.Name]
+		dep.EngineViolations = engineViolations[dep.Path]
+		dep.LicenseViolations = nil
+		if gui.State.Licenses != nil {
+			dep.LicenseViolations = gui.State.Licenses.Violations[dep.Name]
+		}
+	}
+	gui.setDepDeprecations()
+	if gui.State.SortDepsBySize {
+		sortDepsBySize(gui.State.Deps)
+	}
+
+	gui.State.Tarballs, err = gui.NpmManager.GetTarballs(gui.State.Packages)
 	if err != nil {
 		return err
 	}
+	gui.hashTarballs()
+
+	// the global root rarely changes under us, so we only reread it when the
+	// user's looking at it
+	if currentView := gui.g.CurrentView(); !gui.State.GlobalPackagesLoaded || (currentView != nil && currentView.Name() == "global") {
+		if err := gui.refreshStateGlobalPackages(); err != nil {
+			return err
+		}
+	}
 
 	gui.refreshSelectedLine(&gui.State.Panels.Packages.SelectedLine, len(gui.State.Packages))
 	return nil
@@ -120,6 +166,14 @@ func (gui *Gui) handleCheckoutPackage(pkg *commands.Package) error {
 	gui.State.Panels.Deps.SelectedLine = 0
 	gui.State.Panels.Scripts.SelectedLine = 0
 	gui.State.Panels.Tarballs.SelectedLine = 0
+	gui.State.Panels.Vulnerabilities.SelectedLine = 0
+
+	if gui.inDepTree() {
+		return gui.handleExitDepTree()
+	}
+	if gui.inDuplicates() {
+		return gui.handleExitDuplicates()
+	}
 
 	return nil
 }
@@ -131,22 +185,22 @@ func (gui *Gui) handleLinkPackage() error {
 		return nil
 	}
 
-	var cmdStr string
 	if selectedPkg == gui.currentPackage() {
 		return gui.surfaceError(errors.New("Cannot link a package to itself"))
 	}
 
+	pm := gui.currentPackage().PackageManager
+	var cmdStr string
 	if gui.linkPathMap()[selectedPkg.Path] {
-		cmdStr = fmt.Sprintf("npm unlink --no-save %s", selectedPkg.Config.Name)
+		cmdStr = pm.Unlink(selectedPkg.Config.Name)
 	} else {
-		if !selectedPkg.LinkedGlobally {
-			cmdStr = fmt.Sprintf("npm link %s", selectedPkg.Path)
-		} else {
-			cmdStr = fmt.Sprintf("npm link %s", selectedPkg.Config.Name)
-		}
+		cmdStr = pm.Link(selectedPkg.Config.Name, selectedPkg.Path, selectedPkg.LinkedGlobally)
+	}
+	if cmdStr == "" {
+		return gui.surfaceError(errors.New(pm.Name() + " can only link packages that have been linked globally. Globally link " + selectedPkg.Config.Name + " first"))
 	}
 
-	return gui.newMainCommand(cmdStr, selectedPkg.ID(), newMainCommandOptions{})
+	return gui.newMainCommand(cmdStr, selectedPkg.ID(), newMainCommandOptions{onSuccess: gui.invalidateBackgroundChecks})
 }
 
 func (gui *Gui) handleGlobalLinkPackage(pkg *commands.Package) error {
@@ -156,45 +210,99 @@ func (gui *Gui) handleGlobalLinkPackage(pkg *commands.Package) error {
 
 	var cmdStr string
 	if pkg.LinkedGlobally {
-		cmdStr = "npm unlink"
+		cmdStr = pkg.PackageManager.GlobalUnlink(pkg.Config.Name)
 	} else {
-		cmdStr = "npm link"
+		cmdStr = pkg.PackageManager.GlobalLink()
 	}
 
 	return gui.newMainCommand(cmdStr, pkg.ID(), newMainCommandOptions{})
 }
 
-func (gui *Gui) handleInstall(pkg *commands.Package) error {
-	var cmdStr string
+// prefixCmdOpts tells the package manager to target the given package if it's
+// not the current package
+func (gui *Gui) prefixCmdOpts(pkg *commands.Package) commands.CmdOpts {
 	if pkg == gui.currentPackage() {
-		cmdStr = "npm install"
-	} else {
-		cmdStr = "npm install --prefix " + pkg.Path
+		return commands.CmdOpts{}
 	}
-
-	return gui.newMainCommand(cmdStr, pkg.ID(), newMainCommandOptions{})
+	return commands.CmdOpts{Prefix: pkg.Path}
 }
 
-func (gui *Gui) handlePackageUpdate(pkg *commands.Package) error {
-	var cmdStr string
-	if pkg == gui.currentPackage() {
-		cmdStr = "npm update"
-	} else {
-		cmdStr = "npm update --prefix " + pkg.Path
+// cmdOpts is like prefixCmdOpts except that commands against a workspace
+// member are run from the workspace root, scoped to that workspace
+func (gui *Gui) cmdOpts(pkg *commands.Package) commands.CmdOpts {
+	if !pkg.IsWorkspaceMember() {
+		return gui.prefixCmdOpts(pkg)
 	}
 
-	return gui.newMainCommand(cmdStr, pkg.ID(), newMainCommandOptions{})
+	opts := commands.CmdOpts{Workspaces: []string{pkg.Config.Name}}
+	if pkg.WorkspaceRootPath != gui.currentPackage().Path {
+		opts.Prefix = pkg.WorkspaceRootPath
+	}
+	return opts
 }
 
-func (gui *Gui) handleBuild(pkg *commands.Package) error {
-	var cmdStr string
-	if pkg == gui.currentPackage() {
-		cmdStr = "npm run build"
-	} else {
-		cmdStr = "npm run build --prefix " + pkg.Path
+// runPackagesCommand runs a command against each of the given packages.
+// Workspace members sharing a root are targeted with a single command where
+// the package manager allows it. Everything else gets a command of its own,
+// using singleOpts
+func (gui *Gui) runPackagesCommand(pkgs []*commands.Package, singleOpts func(*commands.Package) commands.CmdOpts, mainCommandOpts newMainCommandOptions, getCmdStr func(commands.PackageManager, commands.CmdOpts) string) error {
+	gui.packagesListView().clearSelection()
+
+	groups := [][]*commands.Package{}
+	groupIndices := map[string]int{}
+	for _, pkg := range pkgs {
+		if !pkg.IsWorkspaceMember() {
+			groups = append(groups, []*commands.Package{pkg})
+			continue
+		}
+		key := pkg.PackageManager.Name() + ":" + pkg.WorkspaceRootPath
+		if i, ok := groupIndices[key]; ok {
+			groups[i] = append(groups[i], pkg)
+			continue
+		}
+		groupIndices[key] = len(groups)
+		groups = append(groups, []*commands.Package{pkg})
 	}
 
-	return gui.newMainCommand(cmdStr, pkg.ID(), newMainCommandOptions{})
+	for _, group := range groups {
+		if len(group) > 1 {
+			opts := gui.cmdOpts(group[0])
+			for _, pkg := range group[1:] {
+				opts.Workspaces = append(opts.Workspaces, pkg.Config.Name)
+			}
+			if cmdStr := getCmdStr(group[0].PackageManager, opts); cmdStr != "" {
+				if err := gui.newMainCommand(cmdStr, group[0].ID(), mainCommandOpts); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		for _, pkg := range group {
+			cmdStr := getCmdStr(pkg.PackageManager, singleOpts(pkg))
+			if err := gui.newMainCommand(cmdStr, pkg.ID(), mainCommandOpts); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (gui *Gui) handleInstall(pkgs []*commands.Package) error {
+	return gui.runPackagesCommand(pkgs, gui.cmdOpts, newMainCommandOptions{onSuccess: gui.invalidateBackgroundChecks}, func(pm commands.PackageManager, opts commands.CmdOpts) string {
+		return pm.Install(opts)
+	})
+}
+
+func (gui *Gui) handlePackageUpdate(pkg *commands.Package) error {
+	cmdStr := pkg.PackageManager.Update(gui.cmdOpts(pkg))
+	return gui.newMainCommand(cmdStr, pkg.ID(), newMainCommandOptions{onSuccess: gui.invalidateBackgroundChecks})
+}
+
+func (gui *Gui) handleBuild(pkgs []*commands.Package) error {
+	return gui.runPackagesCommand(pkgs, gui.cmdOpts, newMainCommandOptions{}, func(pm commands.PackageManager, opts commands.CmdOpts) string {
+		return pm.RunScript("build", opts)
+	})
 }
 
 func (gui *Gui) handleOpenPackageConfig(pkg *commands.Package) error {
@@ -208,6 +316,10 @@ func (gui *Gui) handleRemovePackage(pkg *commands.Package) error {
 		return gui.createErrorPanel("Cannot remove only remaining package")
 	}
 
+	if pkg.IsWorkspaceMember() && !utils.IncludesString(gui.Config.GetAppState().RecentPackages, pkg.Path) {
+		return gui.createErrorPanel("Cannot remove a workspace member from the list. Remove its workspace root instead")
+	}
+
 	return gui.createConfirmationPanel(createConfirmationPanelOpts{
 		returnToView:       gui.getPackagesView(),
 		title:              "Remove package",
@@ -239,13 +351,25 @@ func (gui *Gui) handleAddPackage() error {
 	})
 }
 
-func (gui *Gui) handlePackPackage(pkg *commands.Package) error {
-	cmdStr := "npm pack"
-	if pkg != gui.currentPackage() {
-		cmdStr = fmt.Sprintf("npm pack %s", pkg.Path)
-	}
+func (gui *Gui) handlePackPackage(pkgs []*commands.Package) error {
+	return gui.runPackagesCommand(pkgs, gui.prefixCmdOpts, newMainCommandOptions{}, func(pm commands.PackageManager, opts commands.CmdOpts) string {
+		return pm.Pack(opts)
+	})
+}
 
-	return gui.newMainCommand(cmdStr, pkg.ID(), newMainCommandOptions{})
+// handleShowPackList shows what would be published, without having to pack anything
+func (gui *Gui) handleShowPackList(pkg *commands.Package) error {
+	return gui.WithWaitingStatus("listing files", func() error {
+		list, err := gui.NpmManager.GetPackList(pkg)
+		if err != nil {
+			return err
+		}
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.printToMain(presentation.PackListOutput(pkg.Config.Name, list))
+			return nil
+		})
+		return nil
+	})
 }
 
 func (gui *Gui) selectedPackageID() string {
@@ -258,19 +382,16 @@ func (gui *Gui) selectedPackageID() string {
 }
 
 func (gui *Gui) handlePublishPackage(pkg *commands.Package) error {
-	return gui.handlePublish(pkg.Config.Name, pkg.Scoped(), pkg.ID())
+	return gui.handlePublish(pkg.PackageManager, pkg.Path, pkg.Scoped(), pkg.ID())
 }
 
-func (gui *Gui) handlePublish(name string, scoped bool, id string) error {
-	cmdStr := "npm publish"
+func (gui *Gui) handlePublish(pm commands.PackageManager, target string, scoped bool, id string) error {
+	opts := commands.PublishOpts{Target: target}
 
 	tagPrompt := func() error {
 		return gui.createPromptPanel(gui.g.CurrentView(), "Enter tag name (leave blank for no tag)", "", func(tag string) error {
-			if tag != "" {
-				cmdStr = fmt.Sprintf("%s --tag=%s", cmdStr, tag)
-			}
-			cmdStr = fmt.Sprintf("%s %s", cmdStr, name)
-			return gui.newMainCommand(cmdStr, id, newMainCommandOptions{})
+			opts.Tag = tag
+			return gui.newMainCommand(pm.Publish(opts), id, newMainCommandOptions{})
 		})
 	}
 
@@ -279,25 +400,169 @@ func (gui *Gui) handlePublish(name string, scoped bool, id string) error {
 			{
 				displayStrings: []string{"restricted (default)", utils.ColoredString("--access=restricted", color.FgYellow)},
 				onPress: func() error {
-					cmdStr = fmt.Sprintf("%s --access=restricted", cmdStr)
+					opts.Access = "restricted"
 					return tagPrompt()
 				},
 			},
 			{
 				displayStrings: []string{"public", utils.ColoredString("--access=public", color.FgYellow)},
 				onPress: func() error {
-					cmdStr = fmt.Sprintf("%s --access=public", cmdStr)
+					opts.Access = "public"
 					return tagPrompt()
 				},
 			},
 		}
 
-		return gui.createMenu("Set access for publishing scoped package (npm publish)", menuItems, createMenuOptions{showCancel: true})
+		return gui.createMenu(fmt.Sprintf("Set access for publishing scoped package (%s publish)", pm.Name()), menuItems, createMenuOptions{showCancel: true})
 	}
 
 	return tagPrompt()
 }
 
+func (gui *Gui) handleVersionBump(pkg *commands.Package) error {
+	current := pkg.Config.Version
+	// the preview for prerelease bumps assumes we'll stick with the current preid
+	preid := commands.CurrentPreid(current)
+	menuItems := []*menuItem{}
+	for _, increment := range commands.VersionIncrements() {
+		increment := increment
+		version, err := commands.BumpVersion(current, increment, preid)
+		if err != nil {
+			// if the current version isn't valid semver, only a custom version makes sense
+			break
+		}
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: []string{increment, fmt.Sprintf("%s -> %s", current, utils.ColoredString(version, color.FgGreen))},
+			onPress: func() error {
+				if commands.IsPrereleaseIncrement(increment) {
+					return gui.handlePrereleaseBump(pkg, increment, preid)
+				}
+				return gui.handleConfirmVersionBump(pkg, version)
+			},
+		})
+	}
+	menuItems = append(menuItems, &menuItem{
+		displayStrings: []string{"custom", "enter a version"},
+		onPress: func() error {
+			return gui.createPromptPanel(gui.getPackagesView(), "New version", current, func(input string) error {
+				version, err := commands.ValidateVersion(input)
+				if err != nil {
+					return gui.createErrorPanel(err.Error())
+				}
+				return gui.handleConfirmVersionBump(pkg, version)
+			})
+		},
+	})
+
+	return gui.createMenu(fmt.Sprintf("Bump version of %s", pkg.Config.Name), menuItems, createMenuOptions{showCancel: true})
+}
+
+// handlePrereleaseBump asks for the preid (e.g. 'beta') to use, defaulting to
+// the one the current version already has
+func (gui *Gui) handlePrereleaseBump(pkg *commands.Package, increment string, defaultPreid string) error {
+	return gui.createPromptPanel(gui.getPackagesView(), "Prerelease identifier e.g. alpha, beta, rc (leave blank for none)", defaultPreid, func(input string) error {
+		version, err := commands.BumpVersion(pkg.Config.Version, increment, strings.TrimSpace(input))
+		if err != nil {
+			return gui.createErrorPanel(err.Error())
+		}
+		return gui.handleConfirmVersionBump(pkg, version)
+	})
+}
+
+// handleConfirmVersionBump previews the bump and lets the user choose between
+// letting the package manager commit and tag it, or just updating the files
+func (gui *Gui) handleConfirmVersionBump(pkg *commands.Package, version string) error {
+	files := gui.NpmManager.VersionFiles(pkg)
+	gui.renderString("secondary", fmt.Sprintf(
+		"Version: %s -> %s\nFiles: %s",
+		pkg.Config.Version,
+		utils.ColoredString(version, color.FgGreen),
+		utils.ColoredString(strings.Join(files, ", "), color.FgCyan),
+	))
+
+	menuItems := []*menuItem{}
+	if cmdStr := pkg.PackageManager.Version(version, true, gui.cmdOpts(pkg)); cmdStr != "" {
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: []string{"commit and tag", utils.ColoredString(cmdStr, color.FgYellow)},
+			onPress: func() error {
+				return gui.newMainCommand(cmdStr, pkg.ID(), newMainCommandOptions{
+					onSuccess: func() { gui.setPackageVersion(pkg, version) },
+				})
+			},
+		})
+	}
+	// this doesn't run a command: we write the new version into the files ourselves
+	menuItems = append(menuItems, &menuItem{
+		displayStrings: []string{"update files only", utils.ColoredString("writes "+strings.Join(files, ", ")+" directly", color.FgCyan)},
+		onPress: func() error {
+			if err := gui.NpmManager.WriteVersion(pkg, version); err != nil {
+				return gui.createErrorPanel(err.Error())
+			}
+			gui.setPackageVersion(pkg, version)
+			return gui.finalStep(nil)
+		},
+	})
+
+	return gui.createMenu(fmt.Sprintf("Bump %s to %s (%s)", pkg.Config.Name, version, strings.Join(files, ", ")), menuItems, createMenuOptions{showCancel: true})
+}
+
+// setPackageVersion updates the version we show for a package straight away
+// rather than waiting for the package.json to be read again
+func (gui *Gui) setPackageVersion(pkg *commands.Package, version string) {
+	for _, statePkg := range gui.State.Packages {
+		if statePkg.Path == pkg.Path {
+			statePkg.Config.Version = version
+		}
+	}
+	pkg.Config.Version = version
+
+	gui.g.Update(func(g *gocui.Gui) error {
+		if v := g.CurrentView(); v != nil && v.Name() == "packages" {
+			return gui.handlePackageSelect(g, v)
+		}
+		return nil
+	})
+}
+
+func (gui *Gui) handleSetPackageManager(pkg *commands.Package) error {
+	setPackageManager := func(name string) error {
+		appState := gui.Config.GetAppState()
+		if name == "" {
+			delete(appState.PackageManagers, pkg.Path)
+		} else {
+			if appState.PackageManagers == nil {
+				appState.PackageManagers = map[string]string{}
+			}
+			appState.PackageManagers[pkg.Path] = name
+		}
+		if err := gui.Config.SaveAppState(); err != nil {
+			return err
+		}
+		return gui.refreshPackages()
+	}
+
+	detected := commands.DetectPackageManager(pkg.Path, &pkg.Config)
+	menuItems := []*menuItem{
+		{
+			displayStrings: []string{"auto-detect", utils.ColoredString(detected.Name(), color.FgYellow)},
+			onPress: func() error {
+				return setPackageManager("")
+			},
+		},
+	}
+	for _, pm := range commands.PackageManagers() {
+		name := pm.Name()
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: []string{name},
+			onPress: func() error {
+				return setPackageManager(name)
+			},
+		})
+	}
+
+	return gui.createMenu("Set package manager for package", menuItems, createMenuOptions{showCancel: true})
+}
+
 func (gui *Gui) wrappedPackageHandler(f func(*commands.Package) error) func(*gocui.Gui, *gocui.View) error {
 	return gui.wrappedHandler(func() error {
 		pkg := gui.getSelectedPackage()
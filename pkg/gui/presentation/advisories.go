@@ -0,0 +1,72 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/theme"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+func GetAdvisoryListDisplayStrings(advisories []*commands.Advisory) [][]string {
+	lines := make([][]string, len(advisories))
+
+	for i := range advisories {
+		lines[i] = getAdvisoryDisplayStrings(advisories[i])
+	}
+
+	return lines
+}
+
+func getAdvisoryDisplayStrings(a *commands.Advisory) []string {
+	return []string{
+		utils.ColoredString(a.Severity, SeverityColor(a.Severity)),
+		a.Name,
+		utils.ColoredString(a.Range, color.FgMagenta),
+		fixString(a),
+	}
+}
+
+func fixString(a *commands.Advisory) string {
+	switch {
+	case !a.FixAvailable:
+		return utils.ColoredString("no fix", color.FgRed)
+	case a.FixIsBreaking:
+		return utils.ColoredString("breaking fix", color.FgYellow)
+	default:
+		return utils.ColoredString("fix available", color.FgGreen)
+	}
+}
+
+func SeverityColor(severity string) color.Attribute {
+	switch severity {
+	case "critical", "high":
+		return color.FgRed
+	case "moderate":
+		return color.FgYellow
+	case "low":
+		return color.FgCyan
+	default:
+		return theme.DefaultTextColor
+	}
+}
+
+func AdvisorySummary(a *commands.Advisory) string {
+	paths := make([]string, len(a.Paths))
+	for i, path := range a.Paths {
+		paths[i] = "  " + strings.Join(path, " > ")
+	}
+
+	return fmt.Sprintf(
+		"Title: %s\nPackage: %s\nSeverity: %s\nVulnerable versions: %s\nFix: %s\nURL: %s\nPaths:\n%s",
+		utils.ColoredString(a.Title, color.FgYellow),
+		a.Name,
+		utils.ColoredString(a.Severity, SeverityColor(a.Severity)),
+		utils.ColoredString(a.Range, color.FgMagenta),
+		fixString(a),
+		utils.ColoredString(a.URL, color.FgCyan),
+		strings.Join(paths, "\n"),
+	)
+}
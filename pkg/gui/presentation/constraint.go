@@ -0,0 +1,85 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// ConstraintPreviewSummary says in a few words whether the installed version
+// satisfies the constraint, for showing alongside each option in a menu
+func ConstraintPreviewSummary(preview *commands.ConstraintPreview) string {
+	switch {
+	case preview.Err != nil:
+		return utils.ColoredString("not a semver range", color.FgYellow)
+	case preview.Installed == "":
+		return utils.ColoredString("not installed", color.FgHiBlack)
+	case preview.Satisfied:
+		return utils.ColoredString(fmt.Sprintf("%s still satisfies", preview.Installed), color.FgGreen)
+	default:
+		return utils.ColoredString(fmt.Sprintf("%s no longer satisfies", preview.Installed), color.FgRed)
+	}
+}
+
+// ConstraintPreviewOutput explains what changing a dependency's constraint
+// would mean for what's installed, and what a fresh install would pick
+func ConstraintPreviewOutput(name string, oldConstraint string, preview *commands.ConstraintPreview) string {
+	lines := []string{
+		fmt.Sprintf(
+			"Change the constraint of %s from %s to %s?",
+			utils.ColoredString(name, color.FgYellow),
+			utils.ColoredString(oldConstraint, color.FgMagenta),
+			utils.ColoredString(preview.Constraint, color.FgMagenta),
+		),
+		"",
+	}
+
+	if preview.Err != nil {
+		lines = append(lines, utils.ColoredString(fmt.Sprintf("%s is not a semver range, so we can't check it against what's installed", preview.Constraint), color.FgYellow))
+		return strings.Join(lines, "\n")
+	}
+
+	switch {
+	case preview.Installed == "":
+		lines = append(lines, "not currently installed")
+	case preview.Satisfied:
+		lines = append(lines, utils.ColoredString(fmt.Sprintf("installed version %s satisfies the new constraint", preview.Installed), color.FgGreen))
+	default:
+		lines = append(lines, utils.ColoredString(fmt.Sprintf("installed version %s does not satisfy the new constraint", preview.Installed), color.FgRed))
+	}
+
+	if preview.Resolved != "" {
+		lines = append(lines, fmt.Sprintf("a fresh install would pick %s", utils.ColoredString(preview.Resolved, color.FgCyan)))
+	} else {
+		lines = append(lines, utils.ColoredString("none of the available versions satisfy the new constraint", color.FgRed))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ConstraintChangesOutput lists the constraint changes we're about to make to
+// several dependencies, along with those we're leaving alone
+func ConstraintChangesOutput(deps []*commands.Dependency, constraints []string, skipped []*commands.Dependency) string {
+	rows := make([][]string, len(deps))
+	for i, dep := range deps {
+		rows[i] = []string{
+			utils.ColoredString(dep.Name, color.FgYellow),
+			utils.ColoredString(dep.Constraint, color.FgMagenta),
+			"->",
+			utils.ColoredString(constraints[i], color.FgMagenta),
+		}
+	}
+
+	output := fmt.Sprintf("Change these constraints?\n\n%s", utils.RenderDisplayStrings(rows))
+	if len(skipped) > 0 {
+		names := make([]string, len(skipped))
+		for i, dep := range skipped {
+			names[i] = dep.Name
+		}
+		output += "\n\n" + utils.ColoredString(fmt.Sprintf("skipping %s: no installed version to base a constraint on", strings.Join(names, ", ")), color.FgHiBlack)
+	}
+	return output
+}
@@ -10,18 +10,19 @@ import (
 	"github.com/jesseduffield/semver/v3"
 )
 
-func GetDependencyListDisplayStrings(dependencies []*commands.Dependency, commandMap commands.CommandViewMap, wide bool) [][]string {
+// highlighted are the names of dependencies we want to draw the user's attention to
+func GetDependencyListDisplayStrings(dependencies []*commands.Dependency, commandMap commands.CommandViewMap, wide bool, highlighted map[string]bool) [][]string {
 	lines := make([][]string, len(dependencies))
 
 	for i := range dependencies {
 		dep := dependencies[i]
-		lines[i] = getDepDisplayStrings(dep, commandMap[dep.ID()], wide)
+		lines[i] = getDepDisplayStrings(dep, commandMap[dep.ID()], wide, highlighted[dep.Name])
 	}
 
 	return lines
 }
 
-func getDepDisplayStrings(d *commands.Dependency, commandView *commands.CommandView, wide bool) []string {
+func getDepDisplayStrings(d *commands.Dependency, commandView *commands.CommandView, wide bool, highlighted bool) []string {
 	localVersionCol := ""
 	if d.Linked() {
 		localVersionCol = utils.ColoredString("linked: "+d.LinkPath, color.FgCyan)
@@ -36,11 +37,80 @@ func getDepDisplayStrings(d *commands.Dependency, commandView *commands.CommandV
 		localVersionCol = utils.ColoredString("missing", color.FgRed)
 	}
 
+	if d.LockDrift {
+		lockedVersion := "none"
+		if d.Locked != nil {
+			lockedVersion = d.Locked.Version
+		}
+		localVersionCol += utils.ColoredString(fmt.Sprintf(" (lock drift, locked: %s)", lockedVersion), color.FgRed)
+	}
+
+	if len(d.EngineViolations) > 0 {
+		localVersionCol += utils.ColoredString(" (engines)", color.FgRed)
+	}
+
+	if len(d.LicenseViolations) > 0 {
+		localVersionCol += utils.ColoredString(" (license)", color.FgRed)
+	}
+
+	if d.Deprecated != "" {
+		localVersionCol += utils.ColoredString(" (deprecated)", color.FgRed)
+	}
+
+	wantedCol, latestCol := "", ""
+	if d.Outdated != nil {
+		wantedCol = outdatedVersionString(d.Outdated.Current, d.Outdated.Wanted)
+		latestCol = outdatedVersionString(d.Outdated.Current, d.Outdated.Latest)
+	}
+
+	sizeCol := ""
+	if d.DiskUsage != nil {
+		sizeCol = utils.ColoredString(FormatSize(d.DiskUsage.Size), SizeColor(d.DiskUsage.Size))
+	}
+
+	nameAttributes := []color.Attribute{KindColor(d.Kind)}
+	if highlighted {
+		nameAttributes = append(nameAttributes, color.ReverseVideo)
+	}
+
 	return []string{
 		commandView.Status(),
-		utils.ColoredString(truncateWithEllipsis(d.Name, 30, wide), KindColor(d.Kind)),
+		utils.ColoredString(truncateWithEllipsis(d.Name, 30, wide), nameAttributes...),
 		utils.ColoredString(truncateWithEllipsis(d.Constraint, 20, wide), color.FgMagenta),
 		localVersionCol,
+		wantedCol,
+		latestCol,
+		sizeCol,
+	}
+}
+
+// outdatedVersionString is blank if there's nothing newer than what's installed
+func outdatedVersionString(current string, target string) string {
+	if target == "" || target == current {
+		return ""
+	}
+	return utils.ColoredString(target, VersionGapColor(current, target))
+}
+
+// VersionGapColor colours a version by how far ahead of the current version it
+// is: red for a new major version, yellow for minor, and green for patch
+func VersionGapColor(current string, target string) color.Attribute {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return theme.DefaultTextColor
+	}
+	targetVersion, err := semver.NewVersion(target)
+	if err != nil {
+		return theme.DefaultTextColor
+	}
+
+	switch {
+	case targetVersion.Major() != currentVersion.Major():
+		return color.FgRed
+	case targetVersion.Minor() != currentVersion.Minor():
+		return color.FgYellow
+	default:
+		return color.FgGreen
 	}
 }
 
@@ -0,0 +1,50 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+func GetDepTreeDisplayStrings(nodes []*commands.DepNode) [][]string {
+	lines := make([][]string, len(nodes))
+
+	for i := range nodes {
+		lines[i] = getDepNodeDisplayStrings(nodes[i])
+	}
+
+	return lines
+}
+
+func getDepNodeDisplayStrings(n *commands.DepNode) []string {
+	marker := "  "
+	if n.Expanded {
+		marker = "▼ "
+	} else if n.Expandable() {
+		marker = "► "
+	}
+	nameCol := strings.Repeat("  ", n.Depth) + marker + n.Name
+
+	versionCol := ""
+	if n.Missing() {
+		versionCol = utils.ColoredString("missing", color.FgRed)
+	} else if n.PackageConfig != nil {
+		versionCol = utils.ColoredString(n.PackageConfig.Version, color.FgGreen)
+		if _, ok := semverStatus(n.PackageConfig.Version, n.Constraint); !ok {
+			versionCol = utils.ColoredString(fmt.Sprintf("%s (wanted %s)", n.PackageConfig.Version, n.Constraint), color.FgYellow)
+		}
+	}
+
+	markers := []string{}
+	if n.Deduped {
+		markers = append(markers, utils.ColoredString("deduped", color.FgBlue))
+	}
+	if n.Linked() {
+		markers = append(markers, utils.ColoredString("-> "+n.LinkPath, color.FgCyan))
+	}
+
+	return []string{nameCol, versionCol, strings.Join(markers, " ")}
+}
@@ -0,0 +1,40 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// DeprecationString names a deprecated package along with its deprecation message
+func DeprecationString(deprecation *commands.DeprecatedPackage) string {
+	return fmt.Sprintf(
+		"%s: %s",
+		utils.ColoredString(fmt.Sprintf("%s@%s", deprecation.Name, deprecation.Version), color.FgYellow),
+		utils.ColoredString(deprecation.Message, color.FgRed),
+	)
+}
+
+// DeprecationsOutput lists every deprecated package installed, along with the
+// direct dependencies that bring it in
+func DeprecationsOutput(deprecations []*commands.DeprecatedPackage) string {
+	if len(deprecations) == 0 {
+		return utils.ColoredString("no deprecated packages installed", color.FgGreen)
+	}
+
+	lines := []string{
+		utils.ColoredString(fmt.Sprintf("%d deprecated packages installed:", len(deprecations)), color.FgYellow),
+	}
+	for _, deprecation := range deprecations {
+		lines = append(lines,
+			"",
+			DeprecationString(deprecation),
+			fmt.Sprintf("  via %s", utils.ColoredString(strings.Join(deprecation.Via, ", "), color.FgCyan)),
+			fmt.Sprintf("  at %s", utils.ColoredString(deprecation.Path, color.FgBlue)),
+		)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,70 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// max number of packages we list in a dependency's disk usage breakdown
+const maxDiskUsagePackages = 50
+
+// FormatSize renders a number of bytes in a human readable way e.g. '1.5 MB'
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// SizeColor draws attention to the heavier dependencies
+func SizeColor(bytes int64) color.Attribute {
+	switch {
+	case bytes >= 10*1024*1024:
+		return color.FgRed
+	case bytes >= 1024*1024:
+		return color.FgYellow
+	default:
+		return color.FgGreen
+	}
+}
+
+func DiskUsageOutput(dep *commands.Dependency) string {
+	if dep.DiskUsage == nil {
+		return "disk usage unknown: dependency is missing or we haven't finished reading node_modules"
+	}
+	usage := dep.DiskUsage
+
+	lines := []string{
+		fmt.Sprintf("%s takes up %s", utils.ColoredString(dep.Name, KindColor(dep.Kind)), utils.ColoredString(FormatSize(usage.Size), SizeColor(usage.Size))),
+		fmt.Sprintf("plus %s shared with other dependencies", FormatSize(usage.SharedSize)),
+		"",
+		fmt.Sprintf("largest of the %d packages only it pulls in:", len(usage.Packages)),
+	}
+	rows := [][]string{}
+	for i, packageSize := range usage.Packages {
+		if i >= maxDiskUsagePackages {
+			break
+		}
+		rows = append(rows, []string{
+			utils.ColoredString(FormatSize(packageSize.Size), SizeColor(packageSize.Size)),
+			packageSize.Name + "@" + packageSize.Version,
+			utils.ColoredString(packageSize.Path, color.FgCyan),
+		})
+	}
+	lines = append(lines, utils.RenderDisplayStrings(rows))
+	if len(usage.Packages) > maxDiskUsagePackages {
+		lines = append(lines, fmt.Sprintf("... and %d more", len(usage.Packages)-maxDiskUsagePackages))
+	}
+
+	return strings.Join(lines, "\n")
+}
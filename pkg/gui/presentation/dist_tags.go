@@ -0,0 +1,49 @@
+package presentation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// DistTagChangeOutput shows each tag before and after a change along with the
+// command that will make it
+func DistTagChangeOutput(before map[string]string, after map[string]string, cmdStr string) string {
+	tagSet := map[string]bool{}
+	for tag := range before {
+		tagSet[tag] = true
+	}
+	for tag := range after {
+		tagSet[tag] = true
+	}
+	tags := make([]string, 0, len(tagSet))
+	for tag := range tagSet {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	rows := make([][]string, len(tags))
+	for i, tag := range tags {
+		oldVersion, hadTag := before[tag]
+		newVersion, hasTag := after[tag]
+		change := oldVersion
+		switch {
+		case !hadTag:
+			change = utils.ColoredString(fmt.Sprintf("(new) -> %s", newVersion), color.FgGreen)
+		case !hasTag:
+			change = utils.ColoredString(fmt.Sprintf("%s -> (removed)", oldVersion), color.FgRed)
+		case oldVersion != newVersion:
+			change = utils.ColoredString(fmt.Sprintf("%s -> %s", oldVersion, newVersion), color.FgYellow)
+		}
+		rows[i] = []string{utils.ColoredString(tag, color.FgCyan), change}
+	}
+
+	return strings.Join([]string{
+		utils.RenderDisplayStrings(rows),
+		"",
+		utils.ColoredString(cmdStr, color.FgYellow),
+	}, "\n")
+}
@@ -0,0 +1,45 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+func GetDuplicateListDisplayStrings(duplicates []*commands.DuplicatePackage) [][]string {
+	lines := make([][]string, len(duplicates))
+
+	for i, duplicate := range duplicates {
+		versions := make([]string, len(duplicate.Versions))
+		for j, version := range duplicate.Versions {
+			versions[j] = version.Version
+		}
+		lines[i] = []string{
+			duplicate.Name,
+			utils.ColoredString(fmt.Sprintf("%d versions", len(duplicate.Versions)), color.FgRed),
+			utils.ColoredString(strings.Join(versions, ", "), color.FgGreen),
+		}
+	}
+
+	return lines
+}
+
+func DuplicateSummary(d *commands.DuplicatePackage) string {
+	sections := make([]string, len(d.Versions))
+	for i, version := range d.Versions {
+		lines := []string{utils.ColoredString(d.Name+"@"+version.Version, color.FgYellow)}
+		for _, path := range version.Paths {
+			lines = append(lines, "  "+utils.ColoredString(path, color.FgCyan))
+		}
+		if len(version.DirectDependencies) > 0 {
+			lines = append(lines, "  pulled in by: "+strings.Join(version.DirectDependencies, ", "))
+		} else {
+			lines = append(lines, "  not required by anything (extraneous)")
+		}
+		sections[i] = strings.Join(lines, "\n")
+	}
+	return strings.Join(sections, "\n")
+}
@@ -0,0 +1,64 @@
+package presentation
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// EngineViolationString explains a single violation e.g. 'requires node >=14 (running 12.16.1)'
+func EngineViolationString(violation *commands.EngineViolation) string {
+	return fmt.Sprintf(
+		"requires %s %s (running %s)",
+		violation.Engine,
+		utils.ColoredString(violation.Constraint, color.FgMagenta),
+		utils.ColoredString(violation.Installed, color.FgRed),
+	)
+}
+
+// EngineViolationsOutput explains which packages don't support the versions
+// of node and npm we're running. Paths are shown relative to the root package
+func EngineViolationsOutput(violations []*commands.EngineViolation, versions *commands.EngineVersions, rootPath string) string {
+	lines := []string{
+		fmt.Sprintf("Running node %s and npm %s", utils.ColoredString(versionOrUnknown(versions.Node), color.FgCyan), utils.ColoredString(versionOrUnknown(versions.Npm), color.FgCyan)),
+	}
+	if len(violations) == 0 {
+		lines = append(lines, utils.ColoredString("all installed packages support these versions", color.FgGreen))
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines,
+		utils.ColoredString(fmt.Sprintf("%d engine requirements not met:", len(violations)), color.FgYellow),
+		"",
+	)
+	rows := make([][]string, len(violations))
+	for i, violation := range violations {
+		location := "(this package)"
+		if violation.Path != rootPath {
+			if relPath, err := filepath.Rel(rootPath, violation.Path); err == nil {
+				location = relPath
+			} else {
+				location = violation.Path
+			}
+		}
+		rows[i] = []string{
+			utils.ColoredString(fmt.Sprintf("%s@%s", violation.Name, violation.Version), color.FgYellow),
+			EngineViolationString(violation),
+			utils.ColoredString(location, color.FgBlue),
+		}
+	}
+	lines = append(lines, utils.RenderDisplayStrings(rows))
+
+	return strings.Join(lines, "\n")
+}
+
+func versionOrUnknown(version string) string {
+	if version == "" {
+		return "(unknown)"
+	}
+	return version
+}
@@ -0,0 +1,64 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+func GetGlobalPackageListDisplayStrings(pkgs []*commands.GlobalPackage, commandMap commands.CommandViewMap) [][]string {
+	lines := make([][]string, len(pkgs))
+
+	for i := range pkgs {
+		pkg := pkgs[i]
+		lines[i] = getGlobalPackageDisplayStrings(pkg, commandMap[pkg.ID()])
+	}
+
+	return lines
+}
+
+func getGlobalPackageDisplayStrings(p *commands.GlobalPackage, commandView *commands.CommandView) []string {
+	versionCol := ""
+	switch {
+	case p.Linked():
+		versionCol = utils.ColoredString("linked: "+p.LinkPath, color.FgCyan)
+	case p.PackageConfig != nil:
+		versionCol = utils.ColoredString(p.Version(), color.FgGreen)
+	default:
+		versionCol = utils.ColoredString("no package.json", color.FgRed)
+	}
+
+	latestCol := ""
+	if p.Outdated != nil {
+		latestCol = outdatedVersionString(p.Outdated.Current, p.Outdated.Latest)
+	}
+
+	return []string{
+		commandView.Status(),
+		utils.ColoredString(p.Name, color.FgYellow),
+		versionCol,
+		latestCol,
+		utils.ColoredString(strings.Join(p.BinNames(), " "), color.FgMagenta),
+	}
+}
+
+func GlobalPackageSummary(p *commands.GlobalPackage) string {
+	summary := fmt.Sprintf("Name: %s", utils.ColoredString(p.Name, color.FgYellow))
+	if p.PackageConfig != nil {
+		summary = PackageSummary(*p.PackageConfig)
+	}
+	summary = fmt.Sprintf("%s\nPath: %s", summary, utils.ColoredString(p.Path, color.FgCyan))
+	if p.Linked() {
+		summary = fmt.Sprintf("%s\nLinked to: %s", summary, utils.ColoredString(p.LinkPath, color.FgCyan))
+	}
+	if binNames := p.BinNames(); len(binNames) > 0 {
+		summary = fmt.Sprintf("%s\nBin: %s", summary, utils.ColoredString(strings.Join(binNames, ", "), color.FgMagenta))
+	}
+	if p.Outdated != nil {
+		summary = fmt.Sprintf("%s\nLatest: %s", summary, utils.ColoredString(p.Outdated.Latest, VersionGapColor(p.Outdated.Current, p.Outdated.Latest)))
+	}
+	return summary
+}
@@ -0,0 +1,63 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// LicenseViolationString explains why a dependency breaks the license
+// policy, mentioning the package responsible if it's not the dependency itself
+func LicenseViolationString(licensedPackage *commands.LicensedPackage, depName string) string {
+	license := utils.ColoredString(licenseOrNone(licensedPackage.License), color.FgRed)
+	if licensedPackage.Name == depName {
+		return license
+	}
+	return fmt.Sprintf("%s via %s@%s", license, utils.ColoredString(licensedPackage.Name, color.FgYellow), licensedPackage.Version)
+}
+
+// LicenseReportOutput lists installed packages grouped by license, with prod
+// and dev dependencies kept apart
+func LicenseReportOutput(report *commands.LicenseReport) string {
+	lines := []string{}
+	for _, section := range []struct {
+		title  string
+		groups []*commands.LicenseGroup
+	}{
+		{title: "Production dependencies", groups: report.Prod},
+		{title: "Dev dependencies", groups: report.Dev},
+	} {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, utils.ColoredString(section.title, color.Bold))
+		if len(section.groups) == 0 {
+			lines = append(lines, "  none installed")
+			continue
+		}
+
+		for _, group := range section.groups {
+			heading := fmt.Sprintf("%s (%d)", licenseOrNone(group.License), len(group.Packages))
+			if group.Violation != "" {
+				lines = append(lines, fmt.Sprintf("  %s %s", utils.ColoredString(heading, color.FgRed), utils.ColoredString(group.Violation, color.FgRed)))
+			} else {
+				lines = append(lines, "  "+utils.ColoredString(heading, color.FgGreen))
+			}
+			for _, licensedPackage := range group.Packages {
+				lines = append(lines, fmt.Sprintf("    %s@%s %s", licensedPackage.Name, licensedPackage.Version, utils.ColoredString(licensedPackage.Path, color.FgBlue)))
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func licenseOrNone(license string) string {
+	if license == "" {
+		return "(no license)"
+	}
+	return license
+}
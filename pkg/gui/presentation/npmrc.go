@@ -0,0 +1,55 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// NpmConfigOutput shows the effective value of each npm config key along with
+// the layer it came from, followed by the files we looked in. Secrets are masked
+func NpmConfigOutput(config *commands.NpmConfig) string {
+	lines := []string{utils.ColoredString("npm config", color.FgYellow), ""}
+
+	entries := config.Entries()
+	if len(entries) == 0 {
+		lines = append(lines, "nothing set, so npm is using its defaults")
+	} else {
+		rows := make([][]string, len(entries))
+		for i, entry := range entries {
+			source := utils.ColoredString(entry.Source.Name, color.FgBlue)
+			if len(entry.Overridden) > 0 {
+				overridden := make([]string, len(entry.Overridden))
+				for j, layer := range entry.Overridden {
+					overridden[j] = layer.Name
+				}
+				source += utils.ColoredString(fmt.Sprintf(" (overrides %s)", strings.Join(overridden, ", ")), color.FgHiBlack)
+			}
+			rows[i] = []string{
+				utils.ColoredString(entry.Key, color.FgCyan),
+				commands.MaskNpmConfigValue(entry.Key, entry.Value),
+				source,
+			}
+		}
+		lines = append(lines, utils.RenderDisplayStrings(rows))
+	}
+
+	lines = append(lines, "")
+	rows := [][]string{}
+	for _, layer := range config.Layers {
+		if layer.Path == "" {
+			continue
+		}
+		status := utils.ColoredString("not found", color.FgHiBlack)
+		if layer.Exists {
+			status = fmt.Sprintf("%d settings", len(layer.Values))
+		}
+		rows = append(rows, []string{utils.ColoredString(layer.Name, color.FgBlue), layer.Path, status})
+	}
+	lines = append(lines, utils.RenderDisplayStrings(rows))
+
+	return strings.Join(lines, "\n")
+}
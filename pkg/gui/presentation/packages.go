@@ -28,6 +28,9 @@ func getPackageDisplayStrings(p *commands.Package, linkedToCurrentPackage bool,
 	line := utils.ColoredString(p.Config.Name, attr)
 	if isCurrentPkg {
 		line = utils.ColoredString("* ", color.FgGreen) + line
+	} else if p.IsWorkspaceMember() {
+		// workspace members appear beneath their root package
+		line = utils.ColoredString("└ ", color.FgBlue) + line
 	}
 	linkedArg := ""
 	if linkedToCurrentPackage {
@@ -0,0 +1,45 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// PackListOutput shows which files would be published, with anything that
+// looks suspicious listed up top
+func PackListOutput(name string, list *commands.PackList) string {
+	lines := []string{
+		fmt.Sprintf(
+			"%s would publish %d files, %s unpacked",
+			utils.ColoredString(name, color.FgYellow),
+			len(list.Files),
+			utils.ColoredString(FormatSize(list.TotalSize), color.FgCyan),
+		),
+	}
+
+	if warnings := list.Warnings(); len(warnings) > 0 {
+		lines = append(lines, "", utils.ColoredString(fmt.Sprintf("%d suspicious files:", len(warnings)), color.FgRed))
+		rows := make([][]string, len(warnings))
+		for i, file := range warnings {
+			rows[i] = []string{utils.ColoredString(file.Path, color.FgRed), file.Warning}
+		}
+		lines = append(lines, utils.RenderDisplayStrings(rows))
+	}
+
+	lines = append(lines, "")
+	rows := make([][]string, len(list.Files))
+	for i, file := range list.Files {
+		path := file.Path
+		if file.Warning != "" {
+			path = utils.ColoredString(path, color.FgRed)
+		}
+		rows[i] = []string{utils.ColoredString(FormatSize(file.Size), SizeColor(file.Size)), path}
+	}
+	lines = append(lines, utils.RenderDisplayStrings(rows))
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,71 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// PeerIssueString explains a single issue e.g. 'wants react ^17.0.0 (found 18.2.0)'
+func PeerIssueString(issue *commands.PeerIssue) string {
+	found := utils.ColoredString("missing", color.FgRed)
+	if !issue.Unmet() {
+		found = fmt.Sprintf("found %s at %s", utils.ColoredString(issue.Installed, color.FgRed), utils.ColoredString(issue.InstalledPath, color.FgBlue))
+	}
+	optional := ""
+	if issue.Optional {
+		optional = "optional "
+	}
+	return fmt.Sprintf(
+		"wants %speer %s %s (%s)",
+		optional,
+		utils.ColoredString(issue.Peer, color.FgCyan),
+		utils.ColoredString(issue.Constraint, color.FgMagenta),
+		found,
+	)
+}
+
+// PeerIssuesOutput lists unmet peers followed by mismatched ones, with the
+// package requiring each
+func PeerIssuesOutput(issues []*commands.PeerIssue) string {
+	unmet := []*commands.PeerIssue{}
+	mismatched := []*commands.PeerIssue{}
+	for _, issue := range issues {
+		if issue.Unmet() {
+			unmet = append(unmet, issue)
+		} else {
+			mismatched = append(mismatched, issue)
+		}
+	}
+
+	lines := []string{}
+	for _, section := range []struct {
+		title  string
+		issues []*commands.PeerIssue
+	}{
+		{title: "unmet peer dependencies", issues: unmet},
+		{title: "mismatched peer dependencies", issues: mismatched},
+	} {
+		if len(section.issues) == 0 {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, utils.ColoredString(fmt.Sprintf("%d %s:", len(section.issues), section.title), color.FgYellow), "")
+		rows := make([][]string, len(section.issues))
+		for i, issue := range section.issues {
+			rows[i] = []string{
+				utils.ColoredString(fmt.Sprintf("%s@%s", issue.Name, issue.Version), color.FgYellow),
+				PeerIssueString(issue),
+				utils.ColoredString(issue.Path, color.FgBlue),
+			}
+		}
+		lines = append(lines, utils.RenderDisplayStrings(rows))
+	}
+
+	return strings.Join(lines, "\n")
+}
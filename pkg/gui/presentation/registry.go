@@ -0,0 +1,97 @@
+package presentation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// max number of versions we list, newest first
+const maxRegistryVersions = 50
+
+// RegistryPackageOutput shows what the registry knows about a package, much
+// like `npm view` does. installedVersion and constraint are used to point out
+// where the user's dependency sits among the published versions, and may be empty
+func RegistryPackageOutput(pkg *commands.RegistryPackage, installedVersion string, constraint string) string {
+	lines := []string{
+		fmt.Sprintf("%s@%s", utils.ColoredString(pkg.Name, color.FgYellow), utils.ColoredString(pkg.DistTags["latest"], color.FgGreen)),
+	}
+	if pkg.Description != "" {
+		lines = append(lines, pkg.Description)
+	}
+	if latest := pkg.Version(pkg.DistTags["latest"]); latest != nil && latest.Deprecated != "" {
+		lines = append(lines, utils.ColoredString("DEPRECATED: "+latest.Deprecated, color.FgRed))
+	}
+
+	lines = append(lines, "", utils.ColoredString("dist-tags:", color.Bold))
+	tags := make([]string, 0, len(pkg.DistTags))
+	for tag := range pkg.DistTags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	tagRows := make([][]string, len(tags))
+	for i, tag := range tags {
+		tagRows[i] = []string{"  " + utils.ColoredString(tag, color.FgCyan), pkg.DistTags[tag]}
+	}
+	lines = append(lines, utils.RenderDisplayStrings(tagRows))
+
+	if len(pkg.Maintainers) > 0 {
+		lines = append(lines, "", utils.ColoredString("maintainers:", color.Bold))
+		for _, maintainer := range pkg.Maintainers {
+			lines = append(lines, "  "+maintainer)
+		}
+	}
+
+	lines = append(lines, "")
+	if !pkg.Created.IsZero() {
+		lines = append(lines, fmt.Sprintf("created %s, last modified %s", formatDate(pkg.Created), formatDate(pkg.Modified)))
+	}
+	lines = append(lines, utils.ColoredString(fmt.Sprintf("versions (%d):", len(pkg.Versions)), color.Bold))
+
+	versions := pkg.Versions
+	if len(versions) > maxRegistryVersions {
+		versions = versions[:maxRegistryVersions]
+	}
+	versionRows := make([][]string, len(versions))
+	for i, version := range versions {
+		versionCol := version.Version
+		notes := []string{}
+		if tags := pkg.TagsForVersion(version.Version); len(tags) > 0 {
+			notes = append(notes, utils.ColoredString(strings.Join(tags, ", "), color.FgCyan))
+		}
+		if version.Version == installedVersion {
+			versionCol = utils.ColoredString(versionCol, color.FgGreen, color.Bold)
+			notes = append(notes, utils.ColoredString("installed", color.FgGreen))
+		} else if constraint != "" {
+			if _, ok := semverStatus(version.Version, constraint); ok {
+				versionCol = utils.ColoredString(versionCol, color.FgMagenta)
+			}
+		}
+		if version.Deprecated != "" {
+			notes = append(notes, utils.ColoredString("deprecated: "+version.Deprecated, color.FgRed))
+		}
+		versionRows[i] = []string{"  " + versionCol, formatDate(version.Published), strings.Join(notes, " ")}
+	}
+	lines = append(lines, utils.RenderDisplayStrings(versionRows))
+	if len(pkg.Versions) > len(versions) {
+		lines = append(lines, fmt.Sprintf("  ...and %d older versions", len(pkg.Versions)-len(versions)))
+	}
+	if constraint != "" {
+		lines = append(lines, "", fmt.Sprintf("versions satisfying %s are shown in %s", utils.ColoredString(constraint, color.FgMagenta), utils.ColoredString("magenta", color.FgMagenta)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatDate is blank if we don't know the date
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
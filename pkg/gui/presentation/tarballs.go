@@ -2,31 +2,185 @@ package presentation
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/jesseduffield/lazynpm/pkg/commands"
 	"github.com/jesseduffield/lazynpm/pkg/utils"
 )
 
-func GetTarballListDisplayStrings(tarballs []*commands.Tarball, commandMap commands.CommandViewMap) [][]string {
+// comparisonTarball is the tarball picked for comparison, if any
+func GetTarballListDisplayStrings(tarballs []*commands.Tarball, commandMap commands.CommandViewMap, comparisonTarball *commands.Tarball) [][]string {
 	lines := make([][]string, len(tarballs))
 
 	for i := range tarballs {
 		tarball := tarballs[i]
-		lines[i] = getTarballDisplayStrings(tarball, commandMap[tarball.ID()])
+		comparing := comparisonTarball != nil && comparisonTarball.Path == tarball.Path
+		lines[i] = getTarballDisplayStrings(tarball, commandMap[tarball.ID()], comparing)
 	}
 
 	return lines
 }
 
-func getTarballDisplayStrings(t *commands.Tarball, commandView *commands.CommandView) []string {
-	return []string{commandView.Status(), t.Name}
+func getTarballDisplayStrings(t *commands.Tarball, commandView *commands.CommandView, comparing bool) []string {
+	name := t.Name
+	if comparing {
+		name = utils.ColoredString(t.Name, color.ReverseVideo)
+	}
+	annotation := utils.ColoredString(t.PackageName, color.FgCyan)
+	if t.Stale {
+		annotation = utils.ColoredString(t.PackageName+" (stale)", color.FgYellow)
+	}
+	if comparing {
+		annotation = utils.ColoredString("(comparing)", color.FgCyan)
+	}
+	return []string{commandView.Status(), name, annotation}
+}
+
+func TarballSummary(t *commands.Tarball) string {
+	pkgName := t.PackageName
+	if pkgName == "" {
+		pkgName = "unknown"
+	}
+	lines := []string{
+		fmt.Sprintf("Name: %s", utils.ColoredString(t.Name, color.FgYellow)),
+		fmt.Sprintf("Path: %s", utils.ColoredString(t.Path, color.FgCyan)),
+		fmt.Sprintf("Package: %s", utils.ColoredString(pkgName, color.FgYellow)),
+		fmt.Sprintf("Size: %s", utils.ColoredString(FormatSize(t.Size), SizeColor(t.Size))),
+		fmt.Sprintf("Packed: %s", t.ModTime.Format("2006-01-02 15:04:05")),
+	}
+	if t.Hashed {
+		lines = append(lines,
+			fmt.Sprintf("Shasum: %s", t.Shasum),
+			fmt.Sprintf("Integrity: %s", t.Integrity),
+		)
+	} else {
+		lines = append(lines, "Shasum: hashing...", "Integrity: hashing...")
+	}
+	if t.Stale {
+		lines = append(lines, utils.ColoredString("package.json has changed since this tarball was packed", color.FgYellow))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TarballContentsOutput lists what's inside a tarball, so that we can check
+// we're about to publish what we think we are
+func TarballContentsOutput(contents *commands.TarballContents) string {
+	lines := []string{}
+	if pkgConfig := contents.PackageConfig; pkgConfig != nil {
+		lines = append(lines,
+			fmt.Sprintf("%s@%s", utils.ColoredString(pkgConfig.Name, color.FgYellow), utils.ColoredString(pkgConfig.Version, color.FgGreen)),
+		)
+		for _, depMap := range []struct {
+			title string
+			deps  map[string]string
+		}{
+			{title: "dependencies", deps: pkgConfig.Dependencies},
+			{title: "peerDependencies", deps: pkgConfig.PeerDependencies},
+			{title: "optionalDependencies", deps: pkgConfig.OptionalDependencies},
+		} {
+			if len(depMap.deps) == 0 {
+				continue
+			}
+			names := make([]string, 0, len(depMap.deps))
+			for name := range depMap.deps {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			lines = append(lines, fmt.Sprintf("%s (%d):", depMap.title, len(names)))
+			for _, name := range names {
+				lines = append(lines, fmt.Sprintf("  %s %s", name, utils.ColoredString(depMap.deps[name], color.FgMagenta)))
+			}
+		}
+	} else {
+		lines = append(lines, utils.ColoredString("no package.json found in tarball", color.FgRed))
+	}
+
+	lines = append(lines,
+		"",
+		fmt.Sprintf("%d files, %s unpacked", len(contents.Files), utils.ColoredString(FormatSize(contents.UnpackedSize), color.FgCyan)),
+	)
+	rows := make([][]string, len(contents.Files))
+	for i, file := range contents.Files {
+		rows[i] = []string{utils.ColoredString(FormatSize(file.Size), SizeColor(file.Size)), file.Path}
+	}
+	lines = append(lines, utils.RenderDisplayStrings(rows))
+
+	return strings.Join(lines, "\n")
 }
 
-func TarballSummary(s *commands.Tarball) string {
-	return fmt.Sprintf(
-		"Name: %s\nPath: %s",
-		utils.ColoredString(s.Name, color.FgYellow),
-		utils.ColoredString(s.Path, color.FgCyan),
+// TarballDiffOutput shows added, removed, and changed files followed by a
+// unified diff of each changed text file
+func TarballDiffOutput(diff *commands.TarballDiff) string {
+	lines := []string{
+		fmt.Sprintf("Comparing %s with %s", utils.ColoredString(diff.OldName, color.FgYellow), utils.ColoredString(diff.NewName, color.FgYellow)),
+	}
+	if len(diff.Files) == 0 {
+		lines = append(lines, utils.ColoredString(fmt.Sprintf("no differences (%d files)", diff.Unchanged), color.FgGreen))
+		return strings.Join(lines, "\n")
+	}
+
+	counts := map[string]int{}
+	rows := make([][]string, len(diff.Files))
+	for i, file := range diff.Files {
+		counts[file.Status]++
+		size := ""
+		switch file.Status {
+		case "added":
+			size = FormatSize(file.NewSize)
+		case "removed":
+			size = FormatSize(file.OldSize)
+		default:
+			size = fmt.Sprintf("%s -> %s", FormatSize(file.OldSize), FormatSize(file.NewSize))
+		}
+		rows[i] = []string{utils.ColoredString(file.Status, fileDiffColor(file.Status)), file.Path, size}
+	}
+	lines = append(lines,
+		fmt.Sprintf("%d added, %d removed, %d changed, %d unchanged", counts["added"], counts["removed"], counts["changed"], diff.Unchanged),
+		"",
+		utils.RenderDisplayStrings(rows),
 	)
+
+	for _, file := range diff.Files {
+		if file.Status != "changed" {
+			continue
+		}
+		lines = append(lines, "")
+		switch {
+		case file.Binary:
+			lines = append(lines, fmt.Sprintf("binary file %s differs", file.Path))
+		case file.Diff == "":
+			lines = append(lines, fmt.Sprintf("%s is too large to diff", file.Path))
+		default:
+			for _, line := range strings.Split(strings.TrimSuffix(file.Diff, "\n"), "\n") {
+				lines = append(lines, colorDiffLine(line))
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func fileDiffColor(status string) color.Attribute {
+	return map[string]color.Attribute{
+		"added":   color.FgGreen,
+		"removed": color.FgRed,
+		"changed": color.FgYellow,
+	}[status]
+}
+
+func colorDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return utils.ColoredString(line, color.Bold)
+	case strings.HasPrefix(line, "@@"):
+		return utils.ColoredString(line, color.FgCyan)
+	case strings.HasPrefix(line, "+"):
+		return utils.ColoredString(line, color.FgGreen)
+	case strings.HasPrefix(line, "-"):
+		return utils.ColoredString(line, color.FgRed)
+	default:
+		return line
+	}
 }
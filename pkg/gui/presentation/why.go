@@ -0,0 +1,38 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// WhyOutput explains why each installed copy of a package is there, in the
+// spirit of `npm explain`
+func WhyOutput(name string, results []commands.WhyResult) string {
+	if len(results) == 0 {
+		return fmt.Sprintf("%s is not installed in node_modules", utils.ColoredString(name, color.FgYellow))
+	}
+
+	sections := make([]string, len(results))
+	for i, result := range results {
+		lines := []string{
+			fmt.Sprintf("%s %s", utils.ColoredString(result.Package.String(), color.FgYellow), utils.ColoredString(result.Package.Path, color.FgCyan)),
+		}
+		if len(result.Chains) == 0 {
+			lines = append(lines, "  not required by anything (extraneous)")
+		}
+		for _, chain := range result.Chains {
+			steps := make([]string, len(chain.Steps))
+			for j, step := range chain.Steps {
+				steps[j] = step.String()
+			}
+			lines = append(lines, fmt.Sprintf("  %s %s", utils.ColoredString(commands.KindKeyMap()[chain.Kind], KindColor(chain.Kind)), strings.Join(steps, " > ")))
+		}
+		sections[i] = strings.Join(lines, "\n")
+	}
+
+	return strings.Join(sections, "\n\n")
+}
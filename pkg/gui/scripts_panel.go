@@ -36,7 +36,7 @@ func (gui *Gui) handleScriptSelect(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) handleRunScript(script *commands.Script) error {
-	return gui.createPromptPanel(gui.getScriptsView(), "run script", fmt.Sprintf("npm run %s", script.Name), func(input string) error {
+	return gui.createPromptPanel(gui.getScriptsView(), "run script", gui.currentPackage().PackageManager.RunScript(script.Name, gui.cmdOpts(gui.currentPackage())), func(input string) error {
 		return gui.newMainCommand(input, script.ID(), newMainCommandOptions{})
 	})
 }
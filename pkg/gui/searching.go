@@ -2,6 +2,7 @@ package gui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazynpm/pkg/theme"
@@ -32,6 +33,19 @@ func (gui *Gui) handleSearch(g *gocui.Gui, v *gocui.View) error {
 	return nil
 }
 
+func (gui *Gui) isSearchingDeps() bool {
+	return gui.State.Searching.isSearching && gui.State.Searching.view != nil && gui.State.Searching.view.Name() == "deps"
+}
+
+// searchOptions are the keys shown alongside the search results
+func (gui *Gui) searchOptions(options ...string) string {
+	if gui.isSearchingDeps() {
+		options = append(options, fmt.Sprintf("%s: why is it installed?", gui.getKeyDisplay("dependencies.whySearch")))
+	}
+	options = append(options, fmt.Sprintf("%s: exit search mode", gui.getKeyDisplay("universal.return")))
+	return utils.ColoredString(strings.Join(options, ", "), theme.OptionsFgColor)
+}
+
 func (gui *Gui) onSelectItemWrapper(innerFunc func(int) error) func(int, int, int) error {
 	return func(y int, index int, total int) error {
 		if total == 0 {
@@ -40,10 +54,7 @@ func (gui *Gui) onSelectItemWrapper(innerFunc func(int) error) func(int, int, in
 				fmt.Sprintf(
 					"no matches for '%s' %s",
 					gui.State.Searching.searchString,
-					utils.ColoredString(
-						fmt.Sprintf("%s: exit search mode", gui.getKeyDisplay("universal.return")),
-						theme.OptionsFgColor,
-					),
+					gui.searchOptions(),
 				),
 			)
 			return nil
@@ -55,14 +66,9 @@ func (gui *Gui) onSelectItemWrapper(innerFunc func(int) error) func(int, int, in
 				gui.State.Searching.searchString,
 				index+1,
 				total,
-				utils.ColoredString(
-					fmt.Sprintf(
-						"%s: next match, %s: previous match, %s: exit search mode",
-						gui.getKeyDisplay("universal.nextMatch"),
-						gui.getKeyDisplay("universal.prevMatch"),
-						gui.getKeyDisplay("universal.return"),
-					),
-					theme.OptionsFgColor,
+				gui.searchOptions(
+					fmt.Sprintf("%s: next match", gui.getKeyDisplay("universal.nextMatch")),
+					fmt.Sprintf("%s: previous match", gui.getKeyDisplay("universal.prevMatch")),
 				),
 			),
 		)
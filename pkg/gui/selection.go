@@ -0,0 +1,235 @@
+package gui
+
+import (
+	"github.com/fatih/color"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// listSelection is the set of items picked for a bulk action. Items are
+// tracked by ID so that the selection survives a refresh reordering the list.
+// When nothing is selected, actions apply to the item under the cursor.
+type listSelection struct {
+	IDs map[string]bool
+	// RangeStartID is the item a range selection started from. The range runs
+	// from there to the cursor. Empty if we're not selecting a range
+	RangeStartID string
+}
+
+// selectedIDs returns the IDs of the selected items, given the IDs of the
+// items currently in the list and the cursor position
+func (s *listSelection) selectedIDs(itemIDs []string, cursor int) map[string]bool {
+	selected := map[string]bool{}
+	for i, id := range itemIDs {
+		if s.IDs[id] {
+			selected[id] = true
+		}
+		if id == s.RangeStartID {
+			start, end := i, cursor
+			if start > end {
+				start, end = end, start
+			}
+			for j := start; j <= end && j < len(itemIDs); j++ {
+				selected[itemIDs[j]] = true
+			}
+		}
+	}
+	return selected
+}
+
+func (s *listSelection) clear() {
+	s.IDs = nil
+	s.RangeStartID = ""
+}
+
+// selectedIndices returns the indices of the selected items in list order,
+// or just the cursor if nothing is selected
+func (lv *listView) selectedIndices() []int {
+	itemIDs := lv.getItemIDs()
+	cursor := *lv.getSelectedLineIdxPtr()
+	selected := lv.getSelection().selectedIDs(itemIDs, cursor)
+	if len(selected) == 0 {
+		if cursor >= len(itemIDs) {
+			return nil
+		}
+		return []int{cursor}
+	}
+
+	indices := []int{}
+	for i, id := range itemIDs {
+		if selected[id] {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// refreshRangeSelection re-renders the list if we're selecting a range, given
+// moving the cursor changes what's in the range
+func (lv *listView) refreshRangeSelection() {
+	if lv.getSelection == nil || lv.getSelection().RangeStartID == "" {
+		return
+	}
+	lv.gui.refreshListViews()
+}
+
+func (lv *listView) handleToggleSelected(g *gocui.Gui, v *gocui.View) error {
+	itemIDs := lv.getItemIDs()
+	cursor := *lv.getSelectedLineIdxPtr()
+	if cursor >= len(itemIDs) {
+		return nil
+	}
+
+	selection := lv.getSelection()
+	// toggling an item ends any range selection, keeping what it selected
+	ids := selection.selectedIDs(itemIDs, cursor)
+	id := itemIDs[cursor]
+	if selection.RangeStartID == "" {
+		ids[id] = !ids[id]
+	}
+	selection.IDs = ids
+	selection.RangeStartID = ""
+
+	lv.gui.refreshListViews()
+	return nil
+}
+
+func (lv *listView) handleToggleRangeSelect(g *gocui.Gui, v *gocui.View) error {
+	itemIDs := lv.getItemIDs()
+	cursor := *lv.getSelectedLineIdxPtr()
+	if cursor >= len(itemIDs) {
+		return nil
+	}
+
+	selection := lv.getSelection()
+	if selection.RangeStartID == "" {
+		selection.RangeStartID = itemIDs[cursor]
+	} else {
+		selection.IDs = selection.selectedIDs(itemIDs, cursor)
+		selection.RangeStartID = ""
+	}
+
+	lv.gui.refreshListViews()
+	return nil
+}
+
+// handleClearSelection falls back to quitting if there's nothing to clear,
+// given that's what escape does everywhere else
+func (lv *listView) handleClearSelection(g *gocui.Gui, v *gocui.View) error {
+	selection := lv.getSelection()
+	if len(selection.selectedIDs(lv.getItemIDs(), *lv.getSelectedLineIdxPtr())) == 0 {
+		return lv.gui.handleQuit(g, v)
+	}
+
+	lv.clearSelection()
+	return nil
+}
+
+// clearSelection is for bulk actions to call once the user has confirmed
+// them, so that backing out of a menu or confirmation keeps the selection
+func (lv *listView) clearSelection() {
+	lv.getSelection().clear()
+	lv.gui.refreshListViews()
+}
+
+// markSelected adds a column marking the selected items, if any are selected.
+// We leave the display strings alone otherwise so that the list looks the same
+// as ever when you're not using selection
+func markSelected(displayStrings [][]string, lv *listView) [][]string {
+	itemIDs := lv.getItemIDs()
+	selected := lv.getSelection().selectedIDs(itemIDs, *lv.getSelectedLineIdxPtr())
+	if len(selected) == 0 || len(displayStrings) != len(itemIDs) {
+		return displayStrings
+	}
+
+	marked := make([][]string, len(displayStrings))
+	for i, row := range displayStrings {
+		marker := " "
+		if selected[itemIDs[i]] {
+			marker = utils.ColoredString("*", color.FgCyan)
+		}
+		marked[i] = append([]string{marker}, row...)
+	}
+	return marked
+}
+
+func (gui *Gui) getSelectedPackages() []*commands.Package {
+	pkgs := []*commands.Package{}
+	for _, i := range gui.packagesListView().selectedIndices() {
+		pkgs = append(pkgs, gui.State.Packages[i])
+	}
+	return pkgs
+}
+
+func (gui *Gui) getSelectedDependencies() []*commands.Dependency {
+	deps := []*commands.Dependency{}
+	for _, i := range gui.depsListView().selectedIndices() {
+		deps = append(deps, gui.State.Deps[i])
+	}
+	return deps
+}
+
+func (gui *Gui) getSelectedTarballs() []*commands.Tarball {
+	tarballs := []*commands.Tarball{}
+	for _, i := range gui.tarballsListView().selectedIndices() {
+		tarballs = append(tarballs, gui.State.Tarballs[i])
+	}
+	return tarballs
+}
+
+func (gui *Gui) getSelectedGlobalPackages() []*commands.GlobalPackage {
+	pkgs := []*commands.GlobalPackage{}
+	for _, i := range gui.globalListView().selectedIndices() {
+		pkgs = append(pkgs, gui.State.GlobalPackages[i])
+	}
+	return pkgs
+}
+
+// wrappedPackagesHandler calls f with the selected packages, or the package
+// under the cursor if none are selected. f is expected to clear the selection
+// once the action is confirmed so that a later action doesn't pick it up by
+// surprise
+func (gui *Gui) wrappedPackagesHandler(f func([]*commands.Package) error) func(*gocui.Gui, *gocui.View) error {
+	return gui.wrappedHandler(func() error {
+		pkgs := gui.getSelectedPackages()
+		if len(pkgs) == 0 {
+			return nil
+		}
+
+		return gui.finalStep(f(pkgs))
+	})
+}
+
+func (gui *Gui) wrappedDependenciesHandler(f func([]*commands.Dependency) error) func(*gocui.Gui, *gocui.View) error {
+	return gui.wrappedHandler(func() error {
+		deps := gui.getSelectedDependencies()
+		if len(deps) == 0 {
+			return nil
+		}
+
+		return gui.finalStep(f(deps))
+	})
+}
+
+func (gui *Gui) wrappedTarballsHandler(f func([]*commands.Tarball) error) func(*gocui.Gui, *gocui.View) error {
+	return gui.wrappedHandler(func() error {
+		tarballs := gui.getSelectedTarballs()
+		if len(tarballs) == 0 {
+			return nil
+		}
+
+		return gui.finalStep(f(tarballs))
+	})
+}
+
+func (gui *Gui) wrappedGlobalPackagesHandler(f func([]*commands.GlobalPackage) error) func(*gocui.Gui, *gocui.View) error {
+	return gui.wrappedHandler(func() error {
+		pkgs := gui.getSelectedGlobalPackages()
+		if len(pkgs) == 0 {
+			return nil
+		}
+
+		return gui.finalStep(f(pkgs))
+	})
+}
@@ -0,0 +1,88 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListSelectionSelectedIDs(t *testing.T) {
+	type scenario struct {
+		name      string
+		selection listSelection
+		cursor    int
+		expected  map[string]bool
+	}
+
+	itemIDs := []string{"a", "b", "c", "d", "e"}
+
+	scenarios := []scenario{
+		{
+			name:      "nothing selected",
+			selection: listSelection{},
+			cursor:    1,
+			expected:  map[string]bool{},
+		},
+		{
+			name:      "individually selected items",
+			selection: listSelection{IDs: map[string]bool{"a": true, "d": true}},
+			cursor:    1,
+			expected:  map[string]bool{"a": true, "d": true},
+		},
+		{
+			name:      "items deselected after being selected",
+			selection: listSelection{IDs: map[string]bool{"a": true, "b": false}},
+			cursor:    1,
+			expected:  map[string]bool{"a": true},
+		},
+		{
+			name:      "items no longer in the list",
+			selection: listSelection{IDs: map[string]bool{"a": true, "gone": true}},
+			cursor:    0,
+			expected:  map[string]bool{"a": true},
+		},
+		{
+			name:      "range down from the start",
+			selection: listSelection{RangeStartID: "b"},
+			cursor:    3,
+			expected:  map[string]bool{"b": true, "c": true, "d": true},
+		},
+		{
+			name:      "range up from the start",
+			selection: listSelection{RangeStartID: "d"},
+			cursor:    1,
+			expected:  map[string]bool{"b": true, "c": true, "d": true},
+		},
+		{
+			name:      "range of one item",
+			selection: listSelection{RangeStartID: "c"},
+			cursor:    2,
+			expected:  map[string]bool{"c": true},
+		},
+		{
+			name:      "range combined with selected items",
+			selection: listSelection{IDs: map[string]bool{"a": true}, RangeStartID: "d"},
+			cursor:    4,
+			expected:  map[string]bool{"a": true, "d": true, "e": true},
+		},
+		{
+			name:      "range starting from an item no longer in the list",
+			selection: listSelection{RangeStartID: "gone"},
+			cursor:    2,
+			expected:  map[string]bool{},
+		},
+		{
+			name:      "cursor past the end of the list",
+			selection: listSelection{RangeStartID: "d"},
+			cursor:    10,
+			expected:  map[string]bool{"d": true, "e": true},
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.name, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, s.selection.selectedIDs(itemIDs, s.cursor))
+		})
+	}
+}
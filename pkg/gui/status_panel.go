@@ -1,16 +1,26 @@
 package gui
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazynpm/pkg/gui/presentation"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
 )
 
 // never call this on its own, it should only be called from within refreshCommits()
 func (gui *Gui) refreshStatus() {
+	content := gui.currentPackage().Config.Name
+	if count := len(gui.State.EngineViolations); count > 0 {
+		content += " " + utils.ColoredString(fmt.Sprintf("(%d engine issues)", count), color.FgYellow)
+	}
+	if count := len(gui.State.PeerIssues); count > 0 {
+		content += " " + utils.ColoredString(fmt.Sprintf("(%d peer issues)", count), color.FgYellow)
+	}
 	gui.g.Update(func(*gocui.Gui) error {
-		gui.setViewContent(gui.g, gui.getStatusView(), gui.currentPackage().Config.Name)
+		gui.setViewContent(gui.g, gui.getStatusView(), content)
 		return nil
 	})
 }
@@ -55,6 +65,19 @@ func (gui *Gui) handleStatusSelect(g *gocui.Gui, v *gocui.View) error {
 			magenta.Sprint("Become a sponsor (github is matching all donations for 12 months): https://github.com/sponsors/jesseduffield"), // caffeine ain't free
 		}, "\n\n")
 
+	// the registry and auth settings in effect are easy to lose track of when
+	// they're spread across several .npmrc files
+	dashboardString = presentation.NpmConfigOutput(gui.NpmManager.GetNpmConfig(gui.currentPackage())) + "\n\n" + dashboardString
+
+	// problems with what's installed are what the user most needs to know
+	// about, so they go at the top
+	if len(gui.State.PeerIssues) > 0 {
+		dashboardString = presentation.PeerIssuesOutput(gui.State.PeerIssues) + "\n\n" + dashboardString
+	}
+	if len(gui.State.EngineViolations) > 0 {
+		dashboardString = presentation.EngineViolationsOutput(gui.State.EngineViolations, gui.State.EngineVersions, gui.currentPackage().Path) + "\n\n" + dashboardString
+	}
+
 	gui.printToMain(dashboardString)
 	return nil
 }
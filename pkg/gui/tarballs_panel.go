@@ -2,10 +2,13 @@ package gui
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/fatih/color"
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazynpm/pkg/commands"
 	"github.com/jesseduffield/lazynpm/pkg/gui/presentation"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
 )
 
 // list panel functions
@@ -31,8 +34,70 @@ func (gui *Gui) handleTarballSelect(g *gocui.Gui, v *gocui.View) error {
 		return nil
 	}
 	gui.renderString("secondary", presentation.TarballSummary(tarball))
-	gui.activateContextView(tarball.ID())
-	return nil
+
+	// if we've run a command against the tarball, its output is more interesting
+	if gui.State.CommandViewMap[tarball.ID()] != nil {
+		gui.activateContextView(tarball.ID())
+		return nil
+	}
+
+	// big tarballs take a while to read so we do it in the background
+	return gui.WithWaitingStatus("reading tarball", func() error {
+		contents, err := gui.NpmManager.GetTarballContents(tarball)
+		gui.g.Update(func(*gocui.Gui) error {
+			// the user may have moved on in the meantime
+			if gui.currentViewName() != "tarballs" {
+				return nil
+			}
+			if selected := gui.getSelectedTarball(); selected == nil || selected.Path != tarball.Path {
+				return nil
+			}
+			if err != nil {
+				gui.printToMain(utils.ColoredString(fmt.Sprintf("could not read tarball: %s", err), color.FgRed))
+				return nil
+			}
+			gui.printToMain(presentation.TarballContentsOutput(contents))
+			return nil
+		})
+		return nil
+	})
+}
+
+// hashTarballs works out the shasum and integrity of any tarballs we haven't
+// hashed yet. This reads each tarball in full so we do it in the background
+func (gui *Gui) hashTarballs() {
+	if gui.State.HashingTarballs {
+		return
+	}
+	unhashed := []*commands.Tarball{}
+	for _, tarball := range gui.State.Tarballs {
+		if !tarball.Hashed {
+			unhashed = append(unhashed, tarball)
+		}
+	}
+	if len(unhashed) == 0 {
+		return
+	}
+	gui.State.HashingTarballs = true
+
+	_ = gui.WithWaitingStatus("hashing tarballs", func() error {
+		gui.NpmManager.HashTarballs(unhashed)
+
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.State.HashingTarballs = false
+			// this picks up the hashes we just worked out
+			if err := gui.refreshPackages(); err != nil {
+				return err
+			}
+			if gui.currentViewName() == "tarballs" {
+				if tarball := gui.getSelectedTarball(); tarball != nil {
+					gui.renderString("secondary", presentation.TarballSummary(tarball))
+				}
+			}
+			return nil
+		})
+		return nil
+	})
 }
 
 func (gui *Gui) selectedTarballID() string {
@@ -55,28 +120,116 @@ func (gui *Gui) wrappedTarballHandler(f func(*commands.Tarball) error) func(*goc
 	})
 }
 
-func (gui *Gui) handleDeleteTarball(tarball *commands.Tarball) error {
+func (gui *Gui) handleDeleteTarballs(tarballs []*commands.Tarball) error {
+	names := make([]string, len(tarballs))
+	for i, tarball := range tarballs {
+		names[i] = fmt.Sprintf("`%s`", tarball.Name)
+	}
+
+	title := "Remove tarball"
+	if len(tarballs) > 1 {
+		title = fmt.Sprintf("Remove %d tarballs", len(tarballs))
+	}
+
 	return gui.createConfirmationPanel(createConfirmationPanelOpts{
 		returnToView:       gui.getTarballsView(),
 		returnFocusOnClose: true,
-		title:              "Remove tarball",
-		prompt:             fmt.Sprintf("are you sure you want to delete `%s`?", tarball.Name),
+		title:              title,
+		prompt:             fmt.Sprintf("are you sure you want to delete %s?", strings.Join(names, ", ")),
 		handleConfirm: func() error {
-			return gui.finalStep(gui.OSCommand.Remove(tarball.Path))
+			gui.tarballsListView().clearSelection()
+			for _, tarball := range tarballs {
+				if err := gui.OSCommand.Remove(tarball.Path); err != nil {
+					return gui.finalStep(err)
+				}
+			}
+			return gui.finalStep(nil)
 		},
 	})
 }
 
 func (gui *Gui) handleInstallTarball(tarball *commands.Tarball) error {
-	cmdStr := fmt.Sprintf("npm install %s", tarball.Name)
-	return gui.newMainCommand(cmdStr, tarball.ID(), newMainCommandOptions{})
+	cmdStr := gui.currentPackage().PackageManager.InstallTarball(tarball.Path)
+	return gui.newMainCommand(cmdStr, tarball.ID(), newMainCommandOptions{onSuccess: gui.invalidateBackgroundChecks})
 }
 
 func (gui *Gui) handlePublishTarball(tarball *commands.Tarball) error {
 	// saying scoped: true because that forces us to specify whether we want to publish
 	// as public or restricted. Can't know whether it's a scoped tarball just from
 	// the name because the @ is missing
-	return gui.handlePublish(tarball.Name, true, tarball.ID())
+	return gui.handlePublish(gui.currentPackage().PackageManager, tarball.Path, true, tarball.ID())
+}
+
+// handleCompareTarball compares the selected tarball with the one picked
+// earlier. If none was picked, we let the user either pick this one or compare
+// it with its source package.
+func (gui *Gui) handleCompareTarball(tarball *commands.Tarball) error {
+	comparisonTarball := gui.State.ComparisonTarball
+	if comparisonTarball != nil {
+		gui.State.ComparisonTarball = nil
+		if comparisonTarball.Path == tarball.Path {
+			return nil
+		}
+		return gui.showTarballDiff(func() (*commands.TarballDiff, error) {
+			return gui.NpmManager.DiffTarballs(comparisonTarball, tarball)
+		})
+	}
+
+	pkg := gui.tarballSourcePackage(tarball)
+	menuItems := []*menuItem{
+		{
+			displayStrings: []string{"compare with source package", utils.ColoredString(pkg.Config.Name, color.FgYellow)},
+			onPress: func() error {
+				return gui.showTarballDiff(func() (*commands.TarballDiff, error) {
+					return gui.NpmManager.DiffTarballWithPackage(tarball, pkg)
+				})
+			},
+		},
+		{
+			displayStrings: []string{"compare with another tarball", "then press " + gui.getKeyDisplay("tarballs.compare") + " on the other tarball"},
+			onPress: func() error {
+				gui.State.ComparisonTarball = tarball
+				return gui.refreshPackages()
+			},
+		},
+	}
+
+	return gui.createMenu(fmt.Sprintf("Compare %s", tarball.Name), menuItems, createMenuOptions{showCancel: true})
+}
+
+// tarballSourcePackage finds the package that the tarball was packed from,
+// going by its filename or else the name in its package.json, defaulting to
+// the current package
+func (gui *Gui) tarballSourcePackage(tarball *commands.Tarball) *commands.Package {
+	for _, pkg := range gui.State.Packages {
+		if tarball.PackagePath != "" && pkg.Path == tarball.PackagePath {
+			return pkg
+		}
+	}
+	contents, err := gui.NpmManager.GetTarballContents(tarball)
+	if err == nil && contents.PackageConfig != nil {
+		for _, pkg := range gui.State.Packages {
+			if pkg.Config.Name == contents.PackageConfig.Name {
+				return pkg
+			}
+		}
+	}
+	return gui.currentPackage()
+}
+
+// reading every file in both tarballs can take a while so we do it in the background
+func (gui *Gui) showTarballDiff(getDiff func() (*commands.TarballDiff, error)) error {
+	return gui.WithWaitingStatus("comparing", func() error {
+		diff, err := getDiff()
+		if err != nil {
+			return err
+		}
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.printToMain(presentation.TarballDiffOutput(diff))
+			return nil
+		})
+		return nil
+	})
 }
 
 func (gui *Gui) showTarballsView() bool {
@@ -15,6 +15,12 @@ func (gui *Gui) cyclableViews() []string {
 	if len(gui.State.Tarballs) > 0 {
 		viewNames = append(viewNames, "tarballs")
 	}
+	if len(gui.State.Advisories) > 0 {
+		viewNames = append(viewNames, "vulnerabilities")
+	}
+	if len(gui.State.GlobalPackages) > 0 {
+		viewNames = append(viewNames, "global")
+	}
 	return viewNames
 }
 
@@ -87,6 +93,10 @@ func (gui *Gui) newLineFocused(g *gocui.Gui, v *gocui.View) error {
 		return gui.handleScriptSelect(g, v)
 	case "tarballs":
 		return gui.handleTarballSelect(g, v)
+	case "vulnerabilities":
+		return gui.handleAdvisorySelect(g, v)
+	case "global":
+		return gui.handleGlobalPackageSelect(g, v)
 	case "main":
 		v.Highlight = false
 		return nil
@@ -256,6 +266,16 @@ func (gui *Gui) getTarballsView() *gocui.View {
 	return v
 }
 
+func (gui *Gui) getVulnerabilitiesView() *gocui.View {
+	v, _ := gui.g.View("vulnerabilities")
+	return v
+}
+
+func (gui *Gui) getGlobalView() *gocui.View {
+	v, _ := gui.g.View("global")
+	return v
+}
+
 func (gui *Gui) trimmedContent(v *gocui.View) string {
 	return strings.TrimSpace(v.Buffer())
 }
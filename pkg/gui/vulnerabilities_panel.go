@@ -0,0 +1,121 @@
+package gui
+
+import (
+	"github.com/fatih/color"
+	"github.com/go-errors/errors"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazynpm/pkg/commands"
+	"github.com/jesseduffield/lazynpm/pkg/gui/presentation"
+	"github.com/jesseduffield/lazynpm/pkg/utils"
+)
+
+// list panel functions
+
+func (gui *Gui) getSelectedAdvisory() *commands.Advisory {
+	advisories := gui.State.Advisories
+	if len(advisories) == 0 {
+		return nil
+	}
+	return advisories[gui.State.Panels.Vulnerabilities.SelectedLine]
+}
+
+func (gui *Gui) handleAdvisorySelect(g *gocui.Gui, v *gocui.View) error {
+	if !gui.showVulnerabilitiesView() {
+		// we hide the vulnerabilities view when there are no vulnerabilities
+		if err := gui.switchFocus(nil, gui.getScriptsView()); err != nil {
+			return err
+		}
+	}
+
+	advisory := gui.getSelectedAdvisory()
+	if advisory == nil {
+		return nil
+	}
+	gui.renderString("secondary", presentation.AdvisorySummary(advisory))
+	gui.activateContextView(gui.auditContextID())
+	// so that the affected dependencies are highlighted
+	gui.refreshDepsView()
+	return nil
+}
+
+// auditContextID is the key of the command view for audit fixes on the current package
+func (gui *Gui) auditContextID() string {
+	return "audit:" + gui.currentPackage().Path
+}
+
+// highlightedDepNames returns the names of the dependencies affected by the
+// selected advisory, if the vulnerabilities panel is focused
+func (gui *Gui) highlightedDepNames() map[string]bool {
+	highlighted := map[string]bool{}
+	currentView := gui.g.CurrentView()
+	if currentView == nil || currentView.Name() != "vulnerabilities" {
+		return highlighted
+	}
+
+	advisory := gui.getSelectedAdvisory()
+	if advisory == nil {
+		return highlighted
+	}
+	for _, name := range advisory.DirectDependencies() {
+		highlighted[name] = true
+	}
+	return highlighted
+}
+
+// refreshAdvisories audits the current package in the background, given it
+// needs to talk to the registry
+func (gui *Gui) refreshAdvisories() {
+	pkg := gui.currentPackage()
+	gui.State.AuditPackagePath = pkg.Path
+	gui.State.Advisories = nil
+	opts := gui.cmdOpts(pkg)
+
+	_ = gui.WithWaitingStatus("auditing dependencies", func() error {
+		advisories, err := gui.NpmManager.GetAdvisories(pkg, opts)
+		if err != nil {
+			return err
+		}
+
+		gui.g.Update(func(*gocui.Gui) error {
+			// the user may have switched packages in the meantime
+			if gui.State.AuditPackagePath != pkg.Path {
+				return nil
+			}
+			gui.State.Advisories = advisories
+			gui.refreshSelectedLine(&gui.State.Panels.Vulnerabilities.SelectedLine, len(advisories))
+			return gui.refreshPackages()
+		})
+		return nil
+	})
+}
+
+func (gui *Gui) showVulnerabilitiesView() bool {
+	return len(gui.State.Advisories) > 0
+}
+
+func (gui *Gui) handleAuditFix() error {
+	return gui.runAuditFix(false)
+}
+
+func (gui *Gui) handleAuditForceFix() error {
+	return gui.createConfirmationPanel(createConfirmationPanelOpts{
+		returnToView:       gui.getVulnerabilitiesView(),
+		returnFocusOnClose: true,
+		title:              "Force audit fix",
+		prompt:             "this may install breaking changes to your dependencies. Continue?",
+		handleConfirm: func() error {
+			return gui.runAuditFix(true)
+		},
+	})
+}
+
+func (gui *Gui) runAuditFix(force bool) error {
+	pkg := gui.currentPackage()
+	cmdStr := pkg.PackageManager.AuditFix(force, gui.cmdOpts(pkg))
+	if cmdStr == "" {
+		return gui.surfaceError(errors.New(pkg.PackageManager.Name() + " does not support this kind of audit fix"))
+	}
+
+	gui.renderString("secondary", utils.ColoredString(cmdStr, color.FgYellow))
+	return gui.newMainCommand(cmdStr, gui.auditContextID(), newMainCommandOptions{onSuccess: gui.invalidateBackgroundChecks})
+}
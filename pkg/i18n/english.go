@@ -27,6 +27,12 @@ func addEnglish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "DepsTitle",
 			Other: "Dependencies",
+		}, &i18n.Message{
+			ID:    "DependencyTreeTitle",
+			Other: "Dependency tree",
+		}, &i18n.Message{
+			ID:    "DuplicatesTitle",
+			Other: "Duplicate packages",
 		}, &i18n.Message{
 			ID:    "ScriptsTitle",
 			Other: "Scripts",
@@ -235,6 +241,15 @@ func addEnglish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "gotoBottom",
 			Other: "scroll to bottom",
+		}, &i18n.Message{
+			ID:    "toggleSelected",
+			Other: "toggle selected (for bulk actions)",
+		}, &i18n.Message{
+			ID:    "toggleRangeSelect",
+			Other: "start/end range selection",
+		}, &i18n.Message{
+			ID:    "clearSelection",
+			Other: "clear selection",
 		}, &i18n.Message{
 			ID:    "(reset)",
 			Other: "(reset)",
@@ -247,6 +262,12 @@ func addEnglish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "TarballsTitle",
 			Other: "Tarballs",
+		}, &i18n.Message{
+			ID:    "VulnerabilitiesTitle",
+			Other: "Vulnerabilities",
+		}, &i18n.Message{
+			ID:    "GlobalTitle",
+			Other: "Global packages",
 		}, &i18n.Message{
 			ID:    "ConfirmationTitle",
 			Other: "Confirmation",